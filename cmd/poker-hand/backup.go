@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/backup"
+)
+
+func backupCmd(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("o", fmt.Sprintf("poker_hand-%s.json.gz", time.Now().Format("20060102-150405")), "archive file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	a, err := handManager.Backup(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := backup.Write(file, a); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Println("Written", *out)
+	return printJSON(a.Summary())
+}
+
+// restoreCmd loads an archive written by backup. Restoring it again is harmless.
+func restoreCmd(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	check := fs.Bool("check", false, "only validate the archive")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected one archive file")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	a, err := backup.Read(file)
+	if err != nil {
+		return err
+	}
+	if *check {
+		return printJSON(a.Summary())
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	if err := handManager.Restore(ctx, a); err != nil {
+		return err
+	}
+	fmt.Println("Restored", fs.Arg(0))
+	return printJSON(a.Summary())
+}
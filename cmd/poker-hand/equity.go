@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/poker/equity"
+)
+
+// equityCmd prints the all-in equity of the hands or ranges given as
+// arguments, one per player, e.g. equity -board Ah7d2c AsKs "7c7h, 2h2d".
+func equityCmd(args []string) error {
+	fs := flag.NewFlagSet("equity", flag.ExitOnError)
+	var o equity.Options
+	fs.Func("board", "board cards, e.g. Ah7d2c", func(s string) error {
+		cards, err := poker.ParseCards(s)
+		o.Board = cards
+		return err
+	})
+	fs.Func("dead", "dead cards", func(s string) error {
+		cards, err := poker.ParseCards(s)
+		o.Dead = cards
+		return err
+	})
+	fs.IntVar(&o.Iterations, "iterations", 0, "sampled deals, all deals are enumerated when feasible by default")
+	fs.Int64Var(&o.Seed, "seed", 1, "random seed")
+	fs.IntVar(&o.Workers, "workers", 0, "parallel workers, all cores by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("equity needs the hands or ranges of two players at least")
+	}
+
+	ranges := make([]poker.Range, fs.NArg())
+	for i, arg := range fs.Args() {
+		r, err := poker.ParseRange(arg)
+		if err != nil {
+			return fmt.Errorf("player %d: %w", i+1, err)
+		}
+		ranges[i] = r
+	}
+	res, err := equity.Calculate(context.Background(), ranges, o)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/VOVAN1993/poker_hand/internal/export"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func exportCmd(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var (
+		req               export.Request
+		format, out, x, b string
+	)
+	fs.StringVar(&req.What, "what", export.WhatTournaments, "tournaments, stats or series")
+	fs.StringVar(&format, "format", string(export.CSV), "csv, ndjson or json")
+	fs.StringVar(&req.Columns, "columns", "", "comma separated columns, all by default")
+	fs.StringVar(&req.Group, "group", "type", "stats grouping: type, month, day or tag")
+	fs.StringVar(&req.Series, "series", "bankroll", "series: bankroll, bankroll-ev, roi or volume")
+	fs.StringVar(&x, "x", string(stats.AxisDate), "series x axis: date or index")
+	fs.StringVar(&b, "bucket", string(stats.BucketDay), "series bucket: day, week or month")
+	fs.IntVar(&req.SeriesOptions.MAWindow, "ma", 0, "series moving average window")
+	fs.StringVar(&out, "o", "", "output file, stdout by default")
+	f := filterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var err error
+	if req.Format, err = export.ParseFormat(format); err != nil {
+		return err
+	}
+	if req.SeriesOptions.XAxis, err = stats.ParseXAxis(x); err != nil {
+		return err
+	}
+	if req.SeriesOptions.Bucket, err = stats.ParseBucket(b); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	return export.Run(ctx, handManager, *f, req, w)
+}
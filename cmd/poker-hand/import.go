@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/tracker"
+)
+
+// importCmd loads tracker CSV exports given as arguments.
+func importCmd(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var (
+		name, room, comma string
+		mappings          []string
+		o                 hander.CSVImport
+	)
+	fs.StringVar(&name, "profile", "generic", "column mapping profile")
+	fs.StringVar(&room, "room", "", "room of rows without one")
+	fs.StringVar(&comma, "comma", "", "column separator, the profile one by default")
+	fs.Int64Var(&o.Account, "account", 0, "account id to import to, the first account by default")
+	fs.BoolVar(&o.DryRun, "dry-run", false, "only show what would be imported")
+	fs.Func("map", "field=Header overriding the profile, repeatable", func(s string) error {
+		mappings = append(mappings, s)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no files to import")
+	}
+
+	p, err := tracker.GetProfile(name)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		if err := p.Map(m); err != nil {
+			return err
+		}
+	}
+	if room != "" {
+		p.Room = room
+	}
+	if comma != "" {
+		p.Comma = comma
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	for _, path := range fs.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		report, err := handManager.ImportCSV(ctx, file, p, o)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/VOVAN1993/poker_hand/internal/hander"
@@ -11,15 +12,66 @@ import (
 )
 
 func main() {
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "serve":
+		err = serve()
+	case "simulate":
+		err = simulate(args)
+	case "export":
+		err = exportCmd(args)
+	case "import":
+		err = importCmd(args)
+	case "backup":
+		err = backupCmd(args)
+	case "restore":
+		err = restoreCmd(args)
+	case "user":
+		err = userCmd(args)
+	case "equity":
+		err = equityCmd(args)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func serve() error {
 	fmt.Println("poker-hand")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	handManager := hander.NewHandManager()
 	if err := handManager.Start(ctx); err != nil {
-		fmt.Println("error starting hand manager:", err.Error())
-		os.Exit(1)
+		return fmt.Errorf("starting hand manager: %w", err)
+	}
+	if err := handManager.ImportTournaments(ctx); err != nil {
+		return fmt.Errorf("importing tournaments: %w", err)
 	}
 	server := server.NewServer(handManager)
 	server.Start()
+	return nil
+}
+
+// startManager connects to the database without importing new tournaments.
+func startManager(ctx context.Context) (hander.HandManager, error) {
+	handManager := hander.NewHandManager()
+	if err := handManager.Start(ctx); err != nil {
+		return nil, fmt.Errorf("starting hand manager: %w", err)
+	}
+	return handManager, nil
+}
+
+func parseFloat32(s string, dst *float32) error {
+	v, err := strconv.ParseFloat(s, 32)
+	*dst = float32(v)
+	return err
 }
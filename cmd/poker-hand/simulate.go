@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func simulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	var o stats.SimulationOptions
+	fs.Float64Var(&o.Bankroll, "bankroll", 0, "starting bankroll in $")
+	fs.Float64Var(&o.BuyIn, "buyin", 0, "simulated buy-in in $, historical average by default")
+	fs.IntVar(&o.Tournaments, "tournaments", stats.DefaultSimulationTournaments, "tournaments per path")
+	fs.IntVar(&o.Paths, "paths", stats.DefaultSimulationPaths, "number of simulated paths")
+	fs.IntVar(&o.SamplePaths, "samples", 0, "raw paths to print")
+	fs.Int64Var(&o.Seed, "seed", 1, "random seed")
+	f := filterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	tournaments, err := handManager.ListTournaments(ctx, *f)
+	if err != nil {
+		return err
+	}
+	sim, err := stats.Simulate(ctx, tournaments, o)
+	if err != nil {
+		return err
+	}
+	return printJSON(sim)
+}
+
+// filterFlags registers the tournament filters shared by the commands.
+func filterFlags(fs *flag.FlagSet) *hander.Filter {
+	var f hander.Filter
+	fs.Func("from", "first day, 2006-01-02", func(s string) error {
+		t, err := time.Parse(time.DateOnly, s)
+		f.From = t
+		return err
+	})
+	fs.Func("to", "last day, 2006-01-02", func(s string) error {
+		t, err := time.Parse(time.DateOnly, s)
+		f.To = t.AddDate(0, 0, 1)
+		return err
+	})
+	fs.Func("type", "tournament type, comma separated", func(s string) error {
+		for _, t := range strings.Split(s, ",") {
+			f.Types = append(f.Types, poker.TournamentType(strings.TrimSpace(t)))
+		}
+		return nil
+	})
+	fs.Func("tag", "tournaments with any of the tags, comma separated", func(s string) error {
+		f.Tags = append(f.Tags, splitTags(s)...)
+		return nil
+	})
+	fs.Func("exclude-tag", "tournaments without any of the tags, comma separated", func(s string) error {
+		f.NotTags = append(f.NotTags, splitTags(s)...)
+		return nil
+	})
+	fs.Func("min-bi", "minimal buy-in", func(s string) error {
+		return parseFloat32(s, &f.MinBI)
+	})
+	fs.Func("max-bi", "maximal buy-in", func(s string) error {
+		return parseFloat32(s, &f.MaxBI)
+	})
+	fs.Func("player", "player ids, comma separated, the whole team by default", func(s string) error {
+		ids, err := splitIDs(s)
+		f.Players = append(f.Players, ids...)
+		return err
+	})
+	fs.Func("account", "account ids, comma separated", func(s string) error {
+		ids, err := splitIDs(s)
+		f.Accounts = append(f.Accounts, ids...)
+		return err
+	})
+	return &f
+}
+
+func splitIDs(s string) ([]int64, error) {
+	var ids []int64
+	for _, v := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if tag, err := poker.NormalizeTag(t); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
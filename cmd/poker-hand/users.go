@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/auth"
+)
+
+// userCmd lists the users, or creates or changes the named one. With
+// -token it issues an API token for the user and prints its secret.
+func userCmd(args []string) error {
+	fs := flag.NewFlagSet("user", flag.ExitOnError)
+	var (
+		name, role, password, token string
+		ttl                         time.Duration
+		players                     []int64
+	)
+	fs.StringVar(&name, "name", "", "user name, all users are listed without it")
+	fs.StringVar(&role, "role", "", "owner, coach or viewer, a new user is a viewer by default")
+	fs.StringVar(&password, "password", "", "new password")
+	fs.Func("players", "player ids the user sees, comma separated, all by default", func(s string) error {
+		ids, err := splitIDs(s)
+		players = ids
+		return err
+	})
+	fs.StringVar(&token, "token", "", "issue an API token with this name")
+	fs.DurationVar(&ttl, "ttl", 0, "lifetime of the token, it never expires by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	handManager, err := startManager(ctx)
+	if err != nil {
+		return err
+	}
+	defer handManager.Stop()
+	users, err := handManager.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return printJSON(users)
+	}
+
+	var u auth.User
+	for _, other := range users {
+		if other.Name == name {
+			u = other
+		}
+	}
+	if role != "" {
+		if u.Role, err = auth.ParseRole(role); err != nil {
+			return err
+		}
+	}
+	if players != nil {
+		u.Players = players
+	}
+	switch {
+	case u.ID == 0:
+		if password == "" {
+			return errors.New("a new user needs a password")
+		}
+		u.Name = name
+		if u.Role == "" {
+			u.Role = auth.RoleViewer
+		}
+		if u, err = handManager.AddUser(ctx, u, password); err != nil {
+			return err
+		}
+	case role != "" || password != "" || players != nil:
+		if u, err = handManager.UpdateUser(ctx, u, password); err != nil {
+			return err
+		}
+	}
+	if token != "" {
+		t, secret, err := handManager.CreateToken(ctx, u.ID, token, ttl)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Token %q of %s, it is not shown again:\n%s\n", t.Name, u.Name, secret)
+		return nil
+	}
+	return printJSON(u)
+}
@@ -2,14 +2,16 @@ module github.com/VOVAN1993/poker_hand
 
 go 1.23.0
 
-require github.com/jackc/pgx/v5 v5.7.2
+require (
+	github.com/go-echarts/go-echarts/v2 v2.5.0
+	github.com/jackc/pgx/v5 v5.7.2
+	golang.org/x/crypto v0.31.0
+)
 
 require (
-	github.com/go-echarts/go-echarts/v2 v2.5.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
-	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 )
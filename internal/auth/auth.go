@@ -0,0 +1,168 @@
+// Package auth holds the users of the HTTP API, their roles and the
+// password and token hashing.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role decides what a user may change, see User.Can.
+type Role string
+
+const (
+	// RoleOwner runs the instance: changes everything and manages users.
+	RoleOwner Role = "owner"
+	// RoleCoach reads the data of the players and tags and notes their tournaments.
+	RoleCoach Role = "coach"
+	// RoleViewer only reads the data of the players.
+	RoleViewer Role = "viewer"
+)
+
+// Access is what a request needs to do.
+type Access int
+
+const (
+	Read Access = iota
+	Annotate
+	Write
+	Admin
+)
+
+// minPasswordLength keeps out the most obvious passwords.
+const minPasswordLength = 8
+
+type (
+	User struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+		Role Role   `json:"role"`
+		// Players limits the data the user sees, all players when empty.
+		// Owners always see everything.
+		Players []int64 `json:"players"`
+	}
+
+	// Token is an API token or a login session of a user. Only the hash of
+	// the secret is kept.
+	Token struct {
+		ID       int64      `json:"id"`
+		UserID   int64      `json:"user_id"`
+		Name     string     `json:"name"`
+		Hash     string     `json:"-"`
+		Session  bool       `json:"session"`
+		Created  time.Time  `json:"created"`
+		LastUsed *time.Time `json:"last_used,omitempty"`
+		// Expires is zero for tokens that never expire.
+		Expires time.Time `json:"expires,omitempty"`
+	}
+
+	userKey struct{}
+)
+
+func ParseRole(s string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(s))); r {
+	case RoleOwner, RoleCoach, RoleViewer:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown role %q", s)
+}
+
+func (u User) Validate() error {
+	if strings.TrimSpace(u.Name) == "" || strings.ContainsAny(u.Name, " \t\n") {
+		return errors.New("user name is required and cannot have spaces")
+	}
+	if _, err := ParseRole(string(u.Role)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Can tells whether the role of the user allows the access.
+func (u User) Can(a Access) bool {
+	switch u.Role {
+	case RoleOwner:
+		return true
+	case RoleCoach:
+		return a <= Annotate
+	case RoleViewer:
+		return a == Read
+	}
+	return false
+}
+
+// Sees tells whether the user may read the data of the player.
+func (u User) Sees(playerID int64) bool {
+	return u.Role == RoleOwner || len(u.Players) == 0 || slices.Contains(u.Players, playerID)
+}
+
+// Scope narrows the requested players down to those the user sees. An empty
+// request stands for all of them, ok is false when nothing is left.
+func (u User) Scope(requested []int64) ([]int64, bool) {
+	if u.Role == RoleOwner || len(u.Players) == 0 {
+		return requested, true
+	}
+	if len(requested) == 0 {
+		return u.Players, true
+	}
+	var res []int64
+	for _, id := range requested {
+		if slices.Contains(u.Players, id) {
+			res = append(res, id)
+		}
+	}
+	return res, len(res) > 0
+}
+
+func (t Token) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
+
+func HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password is shorter than %d characters", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewSecret returns a random token secret, it is shown to the user once.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashSecret is what is stored for a token. Secrets are random, so a fast
+// hash is enough and lets tokens be looked up by it.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom returns the user of an authenticated request.
+func UserFrom(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
@@ -0,0 +1,316 @@
+// Package backup reads and writes the portable archive of the user data:
+// gzip compressed JSON carrying the schema version it was written with.
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+const (
+	// Format tells archives apart from other gzip files.
+	Format = "poker_hand-backup"
+	// Version is bumped on every change of the archive layout. Archives of
+	// older versions are still read, newer ones are refused.
+	Version = 7
+)
+
+type (
+	Archive struct {
+		Format        string               `json:"format"`
+		Version       int                  `json:"version"`
+		CreatedAt     time.Time            `json:"created_at"`
+		Tournaments   []Tournament         `json:"tournaments"`
+		Transactions  []poker.Transaction  `json:"transactions"`
+		RakebackRules []poker.RakebackRule `json:"rakeback_rules"`
+		Rates         []poker.Rate         `json:"rates"`
+		// Audit is the log of manual changes, since version 2.
+		Audit []poker.AuditEntry `json:"audit"`
+		// Notes are kept since version 3.
+		Notes []poker.Note `json:"notes"`
+		// Players and Accounts are kept since version 4, older archives
+		// belong to the first account of the instance they are restored to.
+		Players  []poker.Player  `json:"players"`
+		Accounts []poker.Account `json:"accounts"`
+		// Deals are the staking deals, since version 5.
+		Deals []poker.Deal `json:"deals"`
+		// EventRules are the merges and splits of recurring events, since version 6.
+		EventRules []poker.EventRule `json:"event_rules"`
+		// Payouts are the known payout structures, since version 7.
+		Payouts []poker.PayoutStructure `json:"payouts"`
+	}
+
+	// Tournament mirrors poker.Tournament with stable field names.
+	Tournament struct {
+		ID             string     `json:"id"`
+		BI             float32    `json:"bi"`
+		BIRake         float32    `json:"bi_rake"`
+		BIBounty       float32    `json:"bi_bounty"`
+		Players        int        `json:"players"`
+		TotalPrizePool float32    `json:"total_prize_pool"`
+		Started        time.Time  `json:"started"`
+		Finished       *time.Time `json:"finished,omitempty"`
+		MyPlace        int        `json:"my_place"`
+		MyPrize        float32    `json:"my_prize"`
+		Reentries      int        `json:"reentries"`
+		Name           string     `json:"name"`
+		Type           string     `json:"type"`
+		Free           bool       `json:"free"`
+		Currency       string     `json:"currency"`
+		Room           string     `json:"room"`
+		// Edited and DeletedAt record manual changes, since version 2.
+		Edited    bool       `json:"edited,omitempty"`
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+		// Tags are kept since version 3.
+		Tags []string `json:"tags,omitempty"`
+		// AccountID is kept since version 4.
+		AccountID int64 `json:"account_id,omitempty"`
+	}
+
+	// Summary counts the records of an archive.
+	Summary struct {
+		Version       int       `json:"version"`
+		CreatedAt     time.Time `json:"created_at"`
+		Tournaments   int       `json:"tournaments"`
+		Transactions  int       `json:"transactions"`
+		RakebackRules int       `json:"rakeback_rules"`
+		Rates         int       `json:"rates"`
+		Audit         int       `json:"audit"`
+		Notes         int       `json:"notes"`
+		Players       int       `json:"players"`
+		Accounts      int       `json:"accounts"`
+		Deals         int       `json:"deals"`
+		EventRules    int       `json:"event_rules"`
+		Payouts       int       `json:"payouts"`
+	}
+)
+
+func NewArchive(created time.Time) Archive {
+	return Archive{
+		Format:        Format,
+		Version:       Version,
+		CreatedAt:     created,
+		Tournaments:   make([]Tournament, 0),
+		Transactions:  make([]poker.Transaction, 0),
+		RakebackRules: make([]poker.RakebackRule, 0),
+		Rates:         make([]poker.Rate, 0),
+		Audit:         make([]poker.AuditEntry, 0),
+		Notes:         make([]poker.Note, 0),
+		Players:       make([]poker.Player, 0),
+		Accounts:      make([]poker.Account, 0),
+		Deals:         make([]poker.Deal, 0),
+		EventRules:    make([]poker.EventRule, 0),
+		Payouts:       make([]poker.PayoutStructure, 0),
+	}
+}
+
+func (a *Archive) AddTournament(t poker.Tournament) {
+	rec := Tournament{
+		ID:             t.ID,
+		BI:             t.BI,
+		BIRake:         t.BIRake,
+		BIBounty:       t.BIBounty,
+		Players:        t.Players,
+		TotalPrizePool: t.TotalPrizePool,
+		Started:        t.Started,
+		MyPlace:        t.MyPlace,
+		MyPrize:        t.MyPrize,
+		Reentries:      t.Reentries,
+		Name:           t.Name,
+		Type:           string(t.Type),
+		Free:           t.Free,
+		Currency:       string(t.Currency),
+		Room:           t.Room,
+		Edited:         t.Edited,
+		Tags:           t.Tags,
+		AccountID:      t.AccountID,
+	}
+	if !t.Finished.IsZero() {
+		rec.Finished = &t.Finished
+	}
+	if !t.Deleted.IsZero() {
+		rec.DeletedAt = &t.Deleted
+	}
+	a.Tournaments = append(a.Tournaments, rec)
+}
+
+func (t Tournament) Poker() poker.Tournament {
+	res := poker.Tournament{
+		ID:             t.ID,
+		BI:             t.BI,
+		BIRake:         t.BIRake,
+		BIBounty:       t.BIBounty,
+		Players:        t.Players,
+		TotalPrizePool: t.TotalPrizePool,
+		Started:        t.Started,
+		MyPlace:        t.MyPlace,
+		MyPrize:        t.MyPrize,
+		Reentries:      t.Reentries,
+		Name:           t.Name,
+		Type:           poker.TournamentType(t.Type),
+		Free:           t.Free,
+		Currency:       poker.Currency(t.Currency),
+		Room:           t.Room,
+		Edited:         t.Edited,
+		Tags:           t.Tags,
+		AccountID:      t.AccountID,
+	}
+	if t.Finished != nil {
+		res.SetFinished(*t.Finished)
+	}
+	if t.DeletedAt != nil {
+		res.Deleted = *t.DeletedAt
+	}
+	return res
+}
+
+func (a Archive) Summary() Summary {
+	return Summary{
+		Version:       a.Version,
+		CreatedAt:     a.CreatedAt,
+		Tournaments:   len(a.Tournaments),
+		Transactions:  len(a.Transactions),
+		RakebackRules: len(a.RakebackRules),
+		Rates:         len(a.Rates),
+		Audit:         len(a.Audit),
+		Notes:         len(a.Notes),
+		Players:       len(a.Players),
+		Accounts:      len(a.Accounts),
+		Deals:         len(a.Deals),
+		EventRules:    len(a.EventRules),
+		Payouts:       len(a.Payouts),
+	}
+}
+
+// Validate checks the archive can be restored by this version.
+func (a Archive) Validate() error {
+	if a.Format != Format {
+		return fmt.Errorf("not a backup archive, format %q", a.Format)
+	}
+	if a.Version < 1 || a.Version > Version {
+		return fmt.Errorf("unsupported backup version %d, this build reads versions 1 to %d", a.Version, Version)
+	}
+	players := make(map[int64]bool, len(a.Players))
+	for _, p := range a.Players {
+		if p.ID <= 0 {
+			return fmt.Errorf("player without id")
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("player %d: %w", p.ID, err)
+		}
+		players[p.ID] = true
+	}
+	for _, acc := range a.Accounts {
+		if acc.ID <= 0 {
+			return fmt.Errorf("account without id")
+		}
+		if err := acc.Validate(); err != nil {
+			return fmt.Errorf("account %d: %w", acc.ID, err)
+		}
+		if !players[acc.PlayerID] {
+			return fmt.Errorf("account %d: no player %d in the archive", acc.ID, acc.PlayerID)
+		}
+	}
+	type key struct {
+		id      string
+		account int64
+	}
+	seen := make(map[key]bool, len(a.Tournaments))
+	for _, t := range a.Tournaments {
+		if t.ID == "" {
+			return fmt.Errorf("tournament without id")
+		}
+		k := key{t.ID, t.AccountID}
+		if seen[k] {
+			return fmt.Errorf("tournament #%s is repeated", t.ID)
+		}
+		seen[k] = true
+	}
+	for _, t := range a.Transactions {
+		if t.ID <= 0 {
+			return fmt.Errorf("transaction without id")
+		}
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("transaction %d: %w", t.ID, err)
+		}
+	}
+	for _, r := range a.RakebackRules {
+		if r.ID <= 0 {
+			return fmt.Errorf("rakeback rule without id")
+		}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rakeback rule %d: %w", r.ID, err)
+		}
+	}
+	for _, d := range a.Deals {
+		if d.ID <= 0 {
+			return fmt.Errorf("deal without id")
+		}
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("deal %d: %w", d.ID, err)
+		}
+		if !players[d.PlayerID] {
+			return fmt.Errorf("deal %d: no player %d in the archive", d.ID, d.PlayerID)
+		}
+	}
+	for _, r := range a.EventRules {
+		if r.ID <= 0 {
+			return fmt.Errorf("event rule without id")
+		}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("event rule %d: %w", r.ID, err)
+		}
+	}
+	for _, s := range a.Payouts {
+		if s.ID <= 0 {
+			return fmt.Errorf("payout structure without id")
+		}
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("payout structure %d: %w", s.ID, err)
+		}
+	}
+	for _, e := range a.Audit {
+		if e.ID <= 0 || e.TournamentID == "" {
+			return fmt.Errorf("audit entry without id")
+		}
+	}
+	for _, n := range a.Notes {
+		if n.ID <= 0 || n.TournamentID == "" {
+			return fmt.Errorf("note without id")
+		}
+	}
+	return nil
+}
+
+func Write(w io.Writer, a Archive) error {
+	gz := gzip.NewWriter(w)
+	gz.Name = fmt.Sprintf("%s-v%d.json", Format, a.Version)
+	gz.ModTime = a.CreatedAt
+	if err := json.NewEncoder(gz).Encode(a); err != nil {
+		gz.Close()
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	return gz.Close()
+}
+
+// Read decodes and validates an archive.
+func Read(r io.Reader) (Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Archive{}, fmt.Errorf("reading backup: %w", err)
+	}
+	defer gz.Close()
+	var a Archive
+	if err := json.NewDecoder(gz).Decode(&a); err != nil {
+		return Archive{}, fmt.Errorf("reading backup: %w", err)
+	}
+	if err := a.Validate(); err != nil {
+		return Archive{}, err
+	}
+	return a, nil
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+type (
+	// Column extracts one exported value from a row of type T.
+	Column[T any] struct {
+		Name  string
+		Value func(T) any
+	}
+)
+
+var TournamentColumns = []Column[poker.Tournament]{
+	{"id", func(t poker.Tournament) any { return t.ID }},
+	{"name", func(t poker.Tournament) any { return t.Name }},
+	{"type", func(t poker.Tournament) any { return string(t.Type) }},
+	{"room", func(t poker.Tournament) any { return t.Room }},
+	{"currency", func(t poker.Tournament) any { return string(t.Currency) }},
+	{"started", func(t poker.Tournament) any { return t.Started }},
+	{"finished", func(t poker.Tournament) any { return t.Finished }},
+	{"duration_min", func(t poker.Tournament) any { return t.Duration.Minutes() }},
+	{"bi", func(t poker.Tournament) any { return t.BI }},
+	{"bi_rake", func(t poker.Tournament) any { return t.BIRake }},
+	{"bi_bounty", func(t poker.Tournament) any { return t.BIBounty }},
+	{"players", func(t poker.Tournament) any { return t.Players }},
+	{"total_prize_pool", func(t poker.Tournament) any { return t.TotalPrizePool }},
+	{"my_place", func(t poker.Tournament) any { return t.MyPlace }},
+	{"my_prize", func(t poker.Tournament) any { return t.MyPrize }},
+	{"reentries", func(t poker.Tournament) any { return t.Reentries }},
+	{"free", func(t poker.Tournament) any { return t.Free }},
+	{"profit", func(t poker.Tournament) any { return t.Profit() }},
+}
+
+var GroupColumns = []Column[stats.Group]{
+	{"key", func(g stats.Group) any { return g.Key }},
+	{"tournaments", func(g stats.Group) any { return g.Tournaments }},
+	{"cost", func(g stats.Group) any { return g.Cost }},
+	{"prize", func(g stats.Group) any { return g.Prize }},
+	{"profit", func(g stats.Group) any { return g.Profit }},
+	{"roi", func(g stats.Group) any { return g.ROI }},
+	{"itm", func(g stats.Group) any { return g.ITM }},
+	{"avg_bi", func(g stats.Group) any { return g.AvgBI }},
+	{"avg_field", func(g stats.Group) any { return g.AvgField }},
+}
+
+var PointColumns = []Column[stats.Point]{
+	{"x", func(p stats.Point) any { return p.X }},
+	{"date", func(p stats.Point) any { return p.Date }},
+	{"tournaments", func(p stats.Point) any { return p.Tournaments }},
+	{"value", func(p stats.Point) any { return p.Value }},
+	{"ma", func(p stats.Point) any { return p.MA }},
+	{"low", func(p stats.Point) any { return p.Low }},
+	{"high", func(p stats.Point) any { return p.High }},
+}
+
+// Select keeps the named columns in the given order, all of them when names is empty.
+func Select[T any](columns []Column[T], names string) ([]Column[T], error) {
+	if strings.TrimSpace(names) == "" {
+		return columns, nil
+	}
+	byName := make(map[string]Column[T], len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+	var res []Column[T]
+	for _, name := range strings.Split(names, ",") {
+		c, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// Table writes rows through w, one call of Row per row.
+type Table[T any] struct {
+	w       Writer
+	columns []Column[T]
+}
+
+func NewTable[T any](w Writer, columns []Column[T]) (*Table[T], error) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	if err := w.WriteHeader(names); err != nil {
+		return nil, err
+	}
+	return &Table[T]{w: w, columns: columns}, nil
+}
+
+func (t *Table[T]) Row(row T) error {
+	values := make([]any, len(t.columns))
+	for i, c := range t.columns {
+		values[i] = c.Value(row)
+	}
+	return t.w.WriteRow(values)
+}
+
+func (t *Table[T]) Close() error {
+	return t.w.Close()
+}
+
+// Rows writes all rows and closes the table.
+func Rows[T any](w Writer, columns []Column[T], rows []T) error {
+	t, err := NewTable(w, columns)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := t.Row(r); err != nil {
+			return err
+		}
+	}
+	return t.Close()
+}
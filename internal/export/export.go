@@ -0,0 +1,117 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+type (
+	// Request describes one export shared by the HTTP endpoints and the CLI.
+	Request struct {
+		// What is tournaments, stats or series.
+		What    string
+		Format  Format
+		Columns string
+		// Group is the stats grouping: type, month, day or tag.
+		Group string
+		// Series is bankroll, bankroll-ev, roi or volume.
+		Series        string
+		SeriesOptions stats.SeriesOptions
+	}
+)
+
+const (
+	WhatTournaments = "tournaments"
+	WhatStats       = "stats"
+	WhatSeries      = "series"
+)
+
+// Validate checks the request before anything is written.
+func (r Request) Validate() error {
+	var err error
+	switch r.What {
+	case WhatTournaments:
+		_, err = Select(TournamentColumns, r.Columns)
+	case WhatStats:
+		if _, err = stats.GroupKey(r.Group); err == nil {
+			_, err = Select(GroupColumns, r.Columns)
+		}
+	case WhatSeries:
+		if _, err = seriesFunc(r.Series); err == nil {
+			_, err = Select(PointColumns, r.Columns)
+		}
+	default:
+		err = fmt.Errorf("unknown export %q", r.What)
+	}
+	return err
+}
+
+// Run writes the export to out. Tournaments are streamed from the database.
+func Run(ctx context.Context, hm hander.HandManager, f hander.Filter, r Request, out io.Writer) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	w := NewWriter(out, r.Format)
+	switch r.What {
+	case WhatTournaments:
+		columns, _ := Select(TournamentColumns, r.Columns)
+		table, err := NewTable(w, columns)
+		if err != nil {
+			return err
+		}
+		if err := hm.IterateTournaments(ctx, f, table.Row); err != nil {
+			return err
+		}
+		return table.Close()
+	case WhatStats:
+		columns, _ := Select(GroupColumns, r.Columns)
+		key, _ := stats.GroupKey(r.Group)
+		tournaments, err := hm.ListTournaments(ctx, f)
+		if err != nil {
+			return err
+		}
+		return Rows(w, columns, stats.GroupByKeys(tournaments, key))
+	default:
+		columns, _ := Select(PointColumns, r.Columns)
+		build, _ := seriesFunc(r.Series)
+		series, err := build(ctx, hm, f, r.SeriesOptions)
+		if err != nil {
+			return err
+		}
+		return Rows(w, columns, series.Points)
+	}
+}
+
+type buildSeries func(ctx context.Context, hm hander.HandManager, f hander.Filter, o stats.SeriesOptions) (stats.Series, error)
+
+func seriesFunc(name string) (buildSeries, error) {
+	fromTournaments := func(build func([]poker.Tournament, stats.SeriesOptions) stats.Series) buildSeries {
+		return func(ctx context.Context, hm hander.HandManager, f hander.Filter, o stats.SeriesOptions) (stats.Series, error) {
+			tournaments, err := hm.ListTournaments(ctx, f)
+			if err != nil {
+				return stats.Series{}, err
+			}
+			return build(tournaments, o), nil
+		}
+	}
+	switch name {
+	case "", "bankroll":
+		return func(ctx context.Context, hm hander.HandManager, f hander.Filter, o stats.SeriesOptions) (stats.Series, error) {
+			return hm.BankrollSeries(ctx, f, o)
+		}, nil
+	case "bankroll-ev":
+		return func(ctx context.Context, hm hander.HandManager, f hander.Filter, o stats.SeriesOptions) (stats.Series, error) {
+			return hm.BankrollEVSeries(ctx, f, o)
+		}, nil
+	case "roi":
+		return fromTournaments(stats.ROI), nil
+	case "volume":
+		return fromTournaments(stats.Volume), nil
+	}
+	return nil, fmt.Errorf("unknown series %q", name)
+}
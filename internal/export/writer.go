@@ -0,0 +1,166 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type (
+	Format string
+
+	// Writer writes rows of a fixed set of columns as they come.
+	Writer interface {
+		WriteHeader(columns []string) error
+		WriteRow(values []any) error
+		Close() error
+	}
+
+	csvWriter struct {
+		w *csv.Writer
+	}
+
+	// jsonWriter writes objects keeping the column order, either as
+	// one array or one object per line.
+	jsonWriter struct {
+		w       *bufio.Writer
+		columns []string
+		lines   bool
+		rows    int
+	}
+)
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	JSON   Format = "json"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return CSV, nil
+	case CSV, NDJSON, JSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q", s)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case JSON:
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+func NewWriter(w io.Writer, f Format) Writer {
+	switch f {
+	case NDJSON:
+		return &jsonWriter{w: bufio.NewWriter(w), lines: true}
+	case JSON:
+		return &jsonWriter{w: bufio.NewWriter(w)}
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatCSV(v)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatCSV(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+func (j *jsonWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	if !j.lines {
+		_, err := j.w.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+func (j *jsonWriter) WriteRow(values []any) error {
+	if !j.lines && j.rows > 0 {
+		if _, err := j.w.WriteString(","); err != nil {
+			return err
+		}
+	}
+	j.rows++
+	if _, err := j.w.WriteString("{"); err != nil {
+		return err
+	}
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok && t.IsZero() {
+			v = nil
+		}
+		key, err := json.Marshal(j.columns[i])
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			j.w.WriteString(",")
+		}
+		j.w.Write(key)
+		j.w.WriteString(":")
+		j.w.Write(value)
+	}
+	_, err := j.w.WriteString("}")
+	if j.lines {
+		_, err = j.w.WriteString("\n")
+	}
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.lines {
+		if _, err := j.w.WriteString("]\n"); err != nil {
+			return err
+		}
+	}
+	return j.w.Flush()
+}
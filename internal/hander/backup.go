@@ -0,0 +1,136 @@
+package hander
+
+import (
+	"context"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/backup"
+	"github.com/VOVAN1993/poker_hand/internal/persistent"
+)
+
+// Backup collects all the user data into an archive.
+func (h *hander) Backup(ctx context.Context) (backup.Archive, error) {
+	a := backup.NewArchive(time.Now().UTC())
+	err := h.ps.IterateTournaments(ctx, func(t persistent.Tournament) error {
+		a.AddTournament(castTournamentFromDB(&t))
+		return nil
+	}, persistent.WithDeleted())
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	txs, err := h.ListTransactions(ctx, Filter{})
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Transactions = append(a.Transactions, txs...)
+	rules, err := h.ListRakebackRules(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.RakebackRules = append(a.RakebackRules, rules...)
+	rates, err := h.ListRates(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Rates = append(a.Rates, rates...)
+	audit, err := h.TournamentAudit(ctx, "", 0)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Audit = append(a.Audit, audit...)
+	notes, err := h.ListNotes(ctx, "", 0)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Notes = append(a.Notes, notes...)
+	players, err := h.ListPlayers(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Players = append(a.Players, players...)
+	accounts, err := h.ListAccounts(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Accounts = append(a.Accounts, accounts...)
+	deals, err := h.ListDeals(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Deals = append(a.Deals, deals...)
+	eventRules, err := h.ListEventRules(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.EventRules = append(a.EventRules, eventRules...)
+	structures, err := h.ListPayoutStructures(ctx)
+	if err != nil {
+		return backup.Archive{}, err
+	}
+	a.Payouts = append(a.Payouts, structures...)
+	return a, nil
+}
+
+// Restore writes an archive back, overwriting the rows it contains. Data of
+// archives without accounts goes to the first account.
+func (h *hander) Restore(ctx context.Context, a backup.Archive) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	owner, err := r.defaultAccount()
+	if err != nil {
+		return err
+	}
+	var d persistent.Dump
+	for _, p := range a.Players {
+		d.Players = append(d.Players, castPlayerToDB(&p))
+	}
+	for _, acc := range a.Accounts {
+		d.Accounts = append(d.Accounts, castAccountToDB(&acc))
+	}
+	for _, rec := range a.Tournaments {
+		t := rec.Poker()
+		if t.AccountID == 0 {
+			t.AccountID = owner.ID
+		}
+		d.Tournaments = append(d.Tournaments, castTournamentToDB(&t))
+	}
+	for _, t := range a.Transactions {
+		if t.PlayerID == 0 {
+			t.PlayerID = owner.PlayerID
+		}
+		d.Transactions = append(d.Transactions, castTransactionToDB(&t))
+	}
+	for _, r := range a.RakebackRules {
+		d.RakebackRules = append(d.RakebackRules, castRakebackRuleToDB(&r))
+	}
+	for _, deal := range a.Deals {
+		d.Deals = append(d.Deals, castDealToDB(&deal))
+	}
+	for _, r := range a.EventRules {
+		d.EventRules = append(d.EventRules, castEventRuleToDB(&r))
+	}
+	for _, s := range a.Payouts {
+		d.Payouts = append(d.Payouts, castPayoutStructureToDB(&s))
+	}
+	for _, r := range a.Rates {
+		d.Rates = append(d.Rates, castRateToDB(&r))
+	}
+	for _, e := range a.Audit {
+		if e.AccountID == 0 {
+			e.AccountID = owner.ID
+		}
+		d.Audit = append(d.Audit, castAuditEntryToDB(&e))
+	}
+	for _, n := range a.Notes {
+		if n.AccountID == 0 {
+			n.AccountID = owner.ID
+		}
+		d.Notes = append(d.Notes, castNoteToDB(&n))
+	}
+	return h.ps.Restore(ctx, d)
+}
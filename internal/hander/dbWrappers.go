@@ -1,14 +1,17 @@
 package hander
 
 import (
+	"github.com/VOVAN1993/poker_hand/internal/auth"
 	"github.com/VOVAN1993/poker_hand/internal/persistent"
 	"github.com/VOVAN1993/poker_hand/internal/poker"
 )
 
 func castTournamentToDB(t *poker.Tournament) persistent.Tournament {
-	return persistent.Tournament{
+	res := persistent.Tournament{
 		ID:             t.ID,
 		BI:             t.BI,
+		BIRake:         t.BIRake,
+		BIBounty:       t.BIBounty,
 		Players:        t.Players,
 		TotalPrizePool: t.TotalPrizePool,
 		Started:        t.Started,
@@ -18,13 +21,28 @@ func castTournamentToDB(t *poker.Tournament) persistent.Tournament {
 		Name:           t.Name,
 		Type:           string(t.Type),
 		Free:           t.Free,
+		Currency:       string(t.Currency),
+		Room:           t.Room,
+		Edited:         t.Edited,
+		Tags:           t.Tags,
+		AccountID:      t.AccountID,
 	}
+	if !t.Finished.IsZero() {
+		res.Finished = &t.Finished
+		res.Duration = &t.Duration
+	}
+	if !t.Deleted.IsZero() {
+		res.DeletedAt = &t.Deleted
+	}
+	return res
 }
 
 func castTournamentFromDB(t *persistent.Tournament) poker.Tournament {
-	return poker.Tournament{
+	res := poker.Tournament{
 		ID:             t.ID,
 		BI:             t.BI,
+		BIRake:         t.BIRake,
+		BIBounty:       t.BIBounty,
 		Players:        t.Players,
 		TotalPrizePool: t.TotalPrizePool,
 		Started:        t.Started,
@@ -34,5 +52,291 @@ func castTournamentFromDB(t *persistent.Tournament) poker.Tournament {
 		Name:           t.Name,
 		Type:           poker.TournamentType(t.Type),
 		Free:           t.Free,
+		Currency:       poker.Currency(t.Currency),
+		Room:           t.Room,
+		Edited:         t.Edited,
+		Tags:           t.Tags,
+		AccountID:      t.AccountID,
+	}
+	if t.Finished != nil {
+		res.Finished = *t.Finished
+	}
+	if t.DeletedAt != nil {
+		res.Deleted = *t.DeletedAt
+	}
+	if t.Duration != nil {
+		res.Duration = *t.Duration
+	}
+	return res
+}
+
+func castTransactionToDB(t *poker.Transaction) persistent.Transaction {
+	return persistent.Transaction{
+		ID:       t.ID,
+		Type:     string(t.Type),
+		Amount:   t.Amount,
+		Currency: string(t.Currency),
+		Date:     t.Date,
+		Room:     t.Room,
+		Note:     t.Note,
+		PlayerID: t.PlayerID,
+	}
+}
+
+func castTransactionFromDB(t *persistent.Transaction) poker.Transaction {
+	return poker.Transaction{
+		ID:       t.ID,
+		Type:     poker.TransactionType(t.Type),
+		Amount:   t.Amount,
+		Currency: poker.Currency(t.Currency),
+		Date:     t.Date,
+		Room:     t.Room,
+		Note:     t.Note,
+		PlayerID: t.PlayerID,
+	}
+}
+
+func castRakebackRuleToDB(r *poker.RakebackRule) persistent.RakebackRule {
+	tiers := make([]persistent.RakebackTier, 0, len(r.Tiers))
+	for _, t := range r.Tiers {
+		tiers = append(tiers, persistent.RakebackTier{Threshold: t.Threshold, Percent: t.Percent})
+	}
+	return persistent.RakebackRule{
+		ID:      r.ID,
+		Name:    r.Name,
+		Room:    r.Room,
+		Percent: r.Percent,
+		Tiers:   tiers,
+	}
+}
+
+func castRakebackRuleFromDB(r *persistent.RakebackRule) poker.RakebackRule {
+	tiers := make([]poker.RakebackTier, 0, len(r.Tiers))
+	for _, t := range r.Tiers {
+		tiers = append(tiers, poker.RakebackTier{Threshold: t.Threshold, Percent: t.Percent})
+	}
+	return poker.RakebackRule{
+		ID:      r.ID,
+		Name:    r.Name,
+		Room:    r.Room,
+		Percent: r.Percent,
+		Tiers:   tiers,
+	}
+}
+
+func castRateToDB(r *poker.Rate) persistent.Rate {
+	return persistent.Rate{
+		Date:     r.Date,
+		Currency: string(r.Currency),
+		USD:      r.USD,
+	}
+}
+
+func castRateFromDB(r *persistent.Rate) poker.Rate {
+	return poker.Rate{
+		Date:     r.Date,
+		Currency: poker.Currency(r.Currency),
+		USD:      r.USD,
+	}
+}
+
+func castAuditEntryToDB(a *poker.AuditEntry) persistent.AuditEntry {
+	changes := make([]persistent.AuditChange, 0, len(a.Changes))
+	for _, c := range a.Changes {
+		changes = append(changes, persistent.AuditChange{Field: c.Field, Old: c.Old, New: c.New})
+	}
+	return persistent.AuditEntry{
+		ID:           a.ID,
+		TournamentID: a.TournamentID,
+		AccountID:    a.AccountID,
+		Action:       string(a.Action),
+		Changes:      changes,
+		At:           a.At,
+	}
+}
+
+func castAuditEntryFromDB(a *persistent.AuditEntry) poker.AuditEntry {
+	changes := make([]poker.FieldChange, 0, len(a.Changes))
+	for _, c := range a.Changes {
+		changes = append(changes, poker.FieldChange{Field: c.Field, Old: c.Old, New: c.New})
+	}
+	return poker.AuditEntry{
+		ID:           a.ID,
+		TournamentID: a.TournamentID,
+		AccountID:    a.AccountID,
+		Action:       poker.AuditAction(a.Action),
+		Changes:      changes,
+		At:           a.At,
+	}
+}
+
+func castNoteToDB(n *poker.Note) persistent.Note {
+	return persistent.Note{
+		ID:           n.ID,
+		TournamentID: n.TournamentID,
+		AccountID:    n.AccountID,
+		Text:         n.Text,
+		Created:      n.Created,
+		Updated:      n.Updated,
+	}
+}
+
+func castNoteFromDB(n *persistent.Note) poker.Note {
+	return poker.Note{
+		ID:           n.ID,
+		TournamentID: n.TournamentID,
+		AccountID:    n.AccountID,
+		Text:         n.Text,
+		Created:      n.Created,
+		Updated:      n.Updated,
+	}
+}
+
+func castPlayerToDB(p *poker.Player) persistent.Player {
+	return persistent.Player{ID: p.ID, Name: p.Name}
+}
+
+func castPlayerFromDB(p *persistent.Player) poker.Player {
+	return poker.Player{ID: p.ID, Name: p.Name}
+}
+
+func castAccountToDB(a *poker.Account) persistent.Account {
+	return persistent.Account{
+		ID:         a.ID,
+		PlayerID:   a.PlayerID,
+		Room:       a.Room,
+		ScreenName: a.ScreenName,
+		SourceDir:  a.SourceDir,
+	}
+}
+
+func castAccountFromDB(a *persistent.Account) poker.Account {
+	return poker.Account{
+		ID:         a.ID,
+		PlayerID:   a.PlayerID,
+		Room:       a.Room,
+		ScreenName: a.ScreenName,
+		SourceDir:  a.SourceDir,
+	}
+}
+
+func castUserToDB(u *auth.User, passwordHash string) persistent.User {
+	return persistent.User{
+		ID:           u.ID,
+		Name:         u.Name,
+		PasswordHash: passwordHash,
+		Role:         string(u.Role),
+		Players:      u.Players,
+	}
+}
+
+func castUserFromDB(u *persistent.User) auth.User {
+	return auth.User{
+		ID:      u.ID,
+		Name:    u.Name,
+		Role:    auth.Role(u.Role),
+		Players: u.Players,
+	}
+}
+
+func castTokenToDB(t *auth.Token) persistent.Token {
+	res := persistent.Token{
+		ID:       t.ID,
+		UserID:   t.UserID,
+		Name:     t.Name,
+		Hash:     t.Hash,
+		Session:  t.Session,
+		Created:  t.Created,
+		LastUsed: t.LastUsed,
+	}
+	if !t.Expires.IsZero() {
+		res.Expires = &t.Expires
+	}
+	return res
+}
+
+func castTokenFromDB(t *persistent.Token) auth.Token {
+	res := auth.Token{
+		ID:       t.ID,
+		UserID:   t.UserID,
+		Name:     t.Name,
+		Hash:     t.Hash,
+		Session:  t.Session,
+		Created:  t.Created,
+		LastUsed: t.LastUsed,
+	}
+	if t.Expires != nil {
+		res.Expires = *t.Expires
+	}
+	return res
+}
+
+func castDealToDB(d *poker.Deal) persistent.Deal {
+	res := persistent.Deal{
+		ID:        d.ID,
+		PlayerID:  d.PlayerID,
+		Backer:    d.Backer,
+		Sold:      d.Sold,
+		Markup:    d.Markup,
+		PlayerCut: d.PlayerCut,
+		Makeup:    string(d.Makeup),
+		Period:    string(d.Period),
+		Start:     d.Start,
+		Note:      d.Note,
+	}
+	if !d.End.IsZero() {
+		res.End = &d.End
+	}
+	return res
+}
+
+func castDealFromDB(d *persistent.Deal) poker.Deal {
+	res := poker.Deal{
+		ID:        d.ID,
+		PlayerID:  d.PlayerID,
+		Backer:    d.Backer,
+		Sold:      d.Sold,
+		Markup:    d.Markup,
+		PlayerCut: d.PlayerCut,
+		Makeup:    poker.MakeupRule(d.Makeup),
+		Period:    poker.Period(d.Period),
+		Start:     d.Start,
+		Note:      d.Note,
+	}
+	if d.End != nil {
+		res.End = *d.End
+	}
+	return res
+}
+
+func castEventRuleToDB(r *poker.EventRule) persistent.EventRule {
+	return persistent.EventRule{ID: r.ID, Match: r.Match, ByName: r.ByName, Key: r.Key}
+}
+
+func castEventRuleFromDB(r *persistent.EventRule) poker.EventRule {
+	return poker.EventRule{ID: r.ID, Match: r.Match, ByName: r.ByName, Key: r.Key}
+}
+
+func castPayoutStructureToDB(s *poker.PayoutStructure) persistent.PayoutStructure {
+	return persistent.PayoutStructure{
+		ID:         s.ID,
+		Name:       s.Name,
+		Type:       string(s.Type),
+		Event:      s.Event,
+		MinPlayers: s.MinPlayers,
+		MaxPlayers: s.MaxPlayers,
+		Places:     s.Places,
+	}
+}
+
+func castPayoutStructureFromDB(s *persistent.PayoutStructure) poker.PayoutStructure {
+	return poker.PayoutStructure{
+		ID:         s.ID,
+		Name:       s.Name,
+		Type:       poker.TournamentType(s.Type),
+		Event:      s.Event,
+		MinPlayers: s.MinPlayers,
+		MaxPlayers: s.MaxPlayers,
+		Places:     s.Places,
 	}
 }
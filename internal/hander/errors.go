@@ -0,0 +1,17 @@
+package hander
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrExists   = errors.New("already exists")
+	ErrInvalid  = errors.New("invalid")
+	// ErrUnauthorized is a wrong password or token, ErrForbidden a role short of the request.
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	// ErrNoHands is an invalid request for hands without hand histories.
+	ErrNoHands = fmt.Errorf("%w: DB_HANDS_DIR environment variable not set", ErrInvalid)
+)
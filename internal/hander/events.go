@@ -0,0 +1,90 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+// SeriesEvents reports the results per recurring event.
+func (h *hander) SeriesEvents(ctx context.Context, f Filter) ([]stats.SeriesEvent, error) {
+	ts, err := h.ListTournaments(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := h.ListEventRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Events(ts, rules), nil
+}
+
+func (h *hander) ListEventRules(ctx context.Context) (poker.EventRules, error) {
+	rules, err := h.ps.ListEventRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(poker.EventRules, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, castEventRuleFromDB(&r))
+	}
+	return res, nil
+}
+
+// MergeEvents counts the series of the keys as the series into.
+func (h *hander) MergeEvents(ctx context.Context, into string, keys ...string) ([]poker.EventRule, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no series to merge", ErrInvalid)
+	}
+	rules := make([]poker.EventRule, 0, len(keys))
+	for _, key := range keys {
+		r := poker.EventRule{Match: poker.EventKey(key), Key: poker.EventKey(into)}
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+		}
+		rules = append(rules, r)
+	}
+	for i := range rules {
+		if err := h.saveEventRule(ctx, &rules[i]); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// SplitEvent moves the tournaments whose title contains match to their own
+// series.
+func (h *hander) SplitEvent(ctx context.Context, match, key string) (poker.EventRule, error) {
+	r := poker.EventRule{Match: strings.TrimSpace(match), ByName: true, Key: poker.EventKey(key)}
+	if err := r.Validate(); err != nil {
+		return poker.EventRule{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	if err := h.saveEventRule(ctx, &r); err != nil {
+		return poker.EventRule{}, err
+	}
+	return r, nil
+}
+
+func (h *hander) saveEventRule(ctx context.Context, r *poker.EventRule) error {
+	id, err := h.ps.SaveEventRule(ctx, castEventRuleToDB(r))
+	if err != nil {
+		return err
+	}
+	r.ID = id
+	return nil
+}
+
+// DeleteEventRule undoes a merge or a split.
+func (h *hander) DeleteEventRule(ctx context.Context, id int64) error {
+	ok, err := h.ps.DeleteEventRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: event rule #%d", ErrNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package hander
+
+import (
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/persistent"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	// Filter narrows down the tournaments returned by the manager.
+	// Zero values mean "no restriction".
+	Filter struct {
+		From  time.Time
+		To    time.Time
+		Types []poker.TournamentType
+		MinBI float32
+		MaxBI float32
+		// Tags keeps tournaments with any of them, NotTags drops those with any of them.
+		Tags    []string
+		NotTags []string
+		// Players keeps the tournaments of any account of theirs, Accounts
+		// those of the accounts themselves. Without both the team is reported.
+		Players  []int64
+		Accounts []int64
+	}
+)
+
+// ledgerOpts narrows down the ledger entries: the date range and the
+// players, the rest of the filter is about tournaments.
+func (f Filter) ledgerOpts() []persistent.WhereOpt {
+	var opts []persistent.WhereOpt
+	if !f.From.IsZero() {
+		opts = append(opts, persistent.WithStartedFrom(f.From))
+	}
+	if !f.To.IsZero() {
+		opts = append(opts, persistent.WithStartedTo(f.To))
+	}
+	if len(f.Players) > 0 {
+		opts = append(opts, persistent.WithPlayers(f.Players...))
+	}
+	return opts
+}
+
+// narrowsTournaments tells whether the filter picks tournaments by more than
+// their players and dates. The ledger belongs to players, so it has no part
+// in a bankroll of such tournaments.
+func (f Filter) narrowsTournaments() bool {
+	return len(f.Types) > 0 || f.MinBI > 0 || f.MaxBI > 0 || len(f.Tags) > 0 || len(f.NotTags) > 0 || len(f.Accounts) > 0
+}
+
+func (f Filter) whereOpts() []persistent.WhereOpt {
+	var opts []persistent.WhereOpt
+	if !f.From.IsZero() {
+		opts = append(opts, persistent.WithStartedFrom(f.From))
+	}
+	if !f.To.IsZero() {
+		opts = append(opts, persistent.WithStartedTo(f.To))
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, 0, len(f.Types))
+		for _, t := range f.Types {
+			types = append(types, string(t))
+		}
+		opts = append(opts, persistent.WithTypes(types...))
+	}
+	if f.MinBI > 0 {
+		opts = append(opts, persistent.WithMinBI(f.MinBI))
+	}
+	if f.MaxBI > 0 {
+		opts = append(opts, persistent.WithMaxBI(f.MaxBI))
+	}
+	if len(f.Tags) > 0 {
+		opts = append(opts, persistent.WithTags(f.Tags...))
+	}
+	if len(f.NotTags) > 0 {
+		opts = append(opts, persistent.WithoutTags(f.NotTags...))
+	}
+	if len(f.Players) > 0 {
+		opts = append(opts, persistent.WithPlayers(f.Players...))
+	}
+	if len(f.Accounts) > 0 {
+		opts = append(opts, persistent.WithAccounts(f.Accounts...))
+	}
+	return opts
+}
@@ -5,32 +5,127 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/VOVAN1993/poker_hand/internal/auth"
+	"github.com/VOVAN1993/poker_hand/internal/backup"
 	"github.com/VOVAN1993/poker_hand/internal/persistent"
 	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+	"github.com/VOVAN1993/poker_hand/internal/tracker"
+)
+
+const (
+	// maxTournamentDuration bounds file based end times, older summaries were most likely copied.
+	maxTournamentDuration = 24 * time.Hour
+	// defaultRoom is where the summaries come from unless DB_ROOM says otherwise.
+	defaultRoom = "GGPoker"
 )
 
 type (
 	HandManager interface {
 		Start(ctx context.Context) error
 		Stop()
+		ImportTournaments(ctx context.Context) error
+		ImportCSV(ctx context.Context, r io.Reader, p tracker.Profile, o CSVImport) (tracker.Report, error)
+
+		Login(ctx context.Context, name, password string) (string, auth.User, error)
+		Logout(ctx context.Context, secret string) error
+		Authenticate(ctx context.Context, secret string) (auth.User, error)
+		ListUsers(ctx context.Context) ([]auth.User, error)
+		AddUser(ctx context.Context, u auth.User, password string) (auth.User, error)
+		UpdateUser(ctx context.Context, u auth.User, password string) (auth.User, error)
+		DeleteUser(ctx context.Context, id int64) error
+		CreateToken(ctx context.Context, userID int64, name string, ttl time.Duration) (auth.Token, string, error)
+		ListTokens(ctx context.Context, userID int64) ([]auth.Token, error)
+		DeleteToken(ctx context.Context, userID, id int64) error
+
+		ListPlayers(ctx context.Context) ([]poker.Player, error)
+		AddPlayer(ctx context.Context, p poker.Player) (poker.Player, error)
+		UpdatePlayer(ctx context.Context, p poker.Player) (poker.Player, error)
+		ListAccounts(ctx context.Context) ([]poker.Account, error)
+		AddAccount(ctx context.Context, a poker.Account) (poker.Account, error)
+		UpdateAccount(ctx context.Context, a poker.Account) (poker.Account, error)
+
+		ListTournaments(ctx context.Context, f Filter) ([]poker.Tournament, error)
+		IterateTournaments(ctx context.Context, f Filter, fn func(poker.Tournament) error) error
+		GetTournament(ctx context.Context, id string, account int64) (poker.Tournament, error)
+		FreeTournament(ctx context.Context, id string, account int64) error
+		AddTournament(ctx context.Context, t poker.Tournament) (poker.Tournament, error)
+		UpdateTournament(ctx context.Context, id string, account int64, p poker.TournamentPatch) (poker.Tournament, error)
+		DeleteTournament(ctx context.Context, id string, account int64) error
+		UndeleteTournament(ctx context.Context, id string, account int64) error
+		TournamentAudit(ctx context.Context, id string, account int64) ([]poker.AuditEntry, error)
+
+		AddTag(ctx context.Context, tournamentID string, account int64, tag string) error
+		RemoveTag(ctx context.Context, tournamentID string, account int64, tag string) error
+		ListTags(ctx context.Context, f Filter) ([]poker.TagUsage, error)
+		ListNotes(ctx context.Context, tournamentID string, account int64) ([]poker.Note, error)
+		AddNote(ctx context.Context, n poker.Note) (poker.Note, error)
+		UpdateNote(ctx context.Context, n poker.Note) (poker.Note, error)
+		DeleteNote(ctx context.Context, n poker.Note) error
+
+		BankrollSeries(ctx context.Context, f Filter, o stats.SeriesOptions) (stats.Series, error)
+		Balance(ctx context.Context, f Filter) (stats.Balance, error)
+		ListTransactions(ctx context.Context, f Filter) ([]poker.Transaction, error)
+		AddTransaction(ctx context.Context, t poker.Transaction) (poker.Transaction, error)
+		DeleteTransaction(ctx context.Context, id int64) error
+
+		ListRakebackRules(ctx context.Context) ([]poker.RakebackRule, error)
+		AddRakebackRule(ctx context.Context, r poker.RakebackRule) (poker.RakebackRule, error)
+		DeleteRakebackRule(ctx context.Context, id int64) error
+		Rakeback(ctx context.Context, f Filter) ([]stats.RakebackMonth, error)
+
+		ListDeals(ctx context.Context) ([]poker.Deal, error)
+		GetDeal(ctx context.Context, id int64) (poker.Deal, error)
+		AddDeal(ctx context.Context, d poker.Deal) (poker.Deal, error)
+		UpdateDeal(ctx context.Context, d poker.Deal) (poker.Deal, error)
+		DeleteDeal(ctx context.Context, id int64) error
+		DealReport(ctx context.Context, id int64) (stats.StakingReport, error)
+
+		SeriesEvents(ctx context.Context, f Filter) ([]stats.SeriesEvent, error)
+		ListEventRules(ctx context.Context) (poker.EventRules, error)
+		MergeEvents(ctx context.Context, into string, keys ...string) ([]poker.EventRule, error)
+		SplitEvent(ctx context.Context, match, key string) (poker.EventRule, error)
+		DeleteEventRule(ctx context.Context, id int64) error
+
+		ListPayoutStructures(ctx context.Context) ([]poker.PayoutStructure, error)
+		AddPayoutStructure(ctx context.Context, s poker.PayoutStructure) (poker.PayoutStructure, error)
+		UpdatePayoutStructure(ctx context.Context, s poker.PayoutStructure) (poker.PayoutStructure, error)
+		DeletePayoutStructure(ctx context.Context, id int64) error
+		PayoutBook(ctx context.Context) (poker.PayoutBook, error)
+		Payouts(ctx context.Context, f Filter) ([]stats.TournamentPayout, error)
+		TournamentPayout(ctx context.Context, id string, account int64) (poker.Payout, error)
+
+		TournamentHands(ctx context.Context, id string, account int64) ([]poker.Hand, error)
+		FinalTableICM(ctx context.Context, id string, account int64) ([]stats.AllInICM, error)
+		TournamentEV(ctx context.Context, id string, account int64) (stats.TournamentEV, error)
+		BankrollEVSeries(ctx context.Context, f Filter, o stats.SeriesOptions) (stats.Series, error)
 
-		ListTournaments(ctx context.Context) ([]poker.Tournament, error)
-		GetTournament(ctx context.Context, id string) (poker.Tournament, error)
-		FreeTournament(ctx context.Context, id string) error
+		ListRates(ctx context.Context) ([]poker.Rate, error)
+		SaveRates(ctx context.Context, rates []poker.Rate) error
+		Statement(ctx context.Context, year int, currency poker.Currency) (stats.Statement, error)
+
+		Backup(ctx context.Context) (backup.Archive, error)
+		Restore(ctx context.Context, a backup.Archive) error
 	}
 	hander struct {
 		ps persistent.Persistent
+
+		evMutex sync.Mutex
+		evs     map[evKey]evEntry
 	}
 )
 
 func NewHandManager() HandManager {
 	db := persistent.NewPersistent()
-	return &hander{ps: db}
+	return &hander{ps: db, evs: make(map[evKey]evEntry)}
 }
 
 func (h *hander) parseTournament(path string) (*poker.Tournament, error) {
@@ -41,20 +136,82 @@ func (h *hander) parseTournament(path string) (*poker.Tournament, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer readFile.Close()
 	fileScanner := bufio.NewScanner(readFile) //default delimiter - ScanLines
 	t, err := poker.ParseTournament(fileScanner)
 	return t, err
 }
 
-func (h *hander) parseTournaments(ctx context.Context) error {
-	tournamentDir := os.Getenv("DB_TOURNAMENT_DIR")
+// ImportTournaments reads the summaries from the source directory of every
+// account. DB_TOURNAMENT_DIR, when no account owns it, is shared: its
+// tournaments go to the first account with the screen name in the summary,
+// in any room, or to the first account.
+func (h *hander) ImportTournaments(ctx context.Context) error {
 	baseDir := os.Getenv("DB_BASE_DIR")
-	if tournamentDir == "" {
-		return errors.New("DB_TOURNAMENT_DIR environment variable not set")
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	dirs := make(map[string]*poker.Account)
+	for i, a := range r.accounts {
+		if a.SourceDir != "" {
+			dirs[a.SourceDir] = &r.accounts[i]
+		}
+	}
+	if dir := os.Getenv("DB_TOURNAMENT_DIR"); dir != "" {
+		if _, ok := dirs[dir]; !ok {
+			dirs[dir] = nil
+		}
+	}
+	if len(dirs) == 0 {
+		return errors.New("no account has a source dir and DB_TOURNAMENT_DIR environment variable not set")
+	}
+	fallback, err := r.defaultAccount()
+	if err != nil {
+		return err
+	}
+
+	handTimes, err := h.readHandTimes(baseDir)
+	if err != nil {
+		return err
 	}
 
 	tournaments := make([]*poker.Tournament, 0)
-	err := filepath.Walk(path.Join(baseDir, tournamentDir), func(path string, info os.FileInfo, err error) error {
+	for dir, owner := range dirs {
+		err = h.walkTournaments(path.Join(baseDir, dir), func(t *poker.Tournament, info os.FileInfo) {
+			a := owner
+			if a == nil {
+				found, ok := r.byScreenName(t.Hero)
+				if !ok {
+					found = fallback
+				}
+				a = &found
+			}
+			setFinished(t, handTimes, info)
+			t.AccountID, t.Room = a.ID, a.Room
+			tournaments = append(tournaments, t)
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	newTournaments := 0
+	for _, t := range tournaments {
+		ok, err := h.ps.SaveTournaments(ctx, castTournamentToDB(t))
+		if err != nil {
+			return err
+		}
+		if ok {
+			newTournaments++
+		}
+	}
+	h.forgetEVs()
+	fmt.Printf("Saved %d tournamets\n", newTournaments)
+	return nil
+}
+
+func (h *hander) walkTournaments(dir string, fn func(*poker.Tournament, os.FileInfo)) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			fmt.Println(err)
 			return err
@@ -71,35 +228,109 @@ func (h *hander) parseTournaments(ctx context.Context) error {
 		if t == nil {
 			return nil
 		}
-		tournaments = append(tournaments, t)
+		fn(t, info)
 		return nil
 	})
+}
+
+// readHandTimes collects the time of the last hand of every tournament from
+// the hand histories in DB_HANDS_DIR, if it is set.
+func (h *hander) readHandTimes(baseDir string) (map[string]time.Time, error) {
+	last := make(map[string]time.Time)
+	err := walkHandHistories(baseDir, func(s *bufio.Scanner) error {
+		return poker.ScanHandTimes(s, last)
+	})
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	newTournaments := 0
-	for _, t := range tournaments {
-		ok, err := h.ps.SaveTournaments(ctx, castTournamentToDB(t))
+	return last, nil
+}
+
+// walkHandHistories passes every hand history file in DB_HANDS_DIR to fn,
+// nothing is read when it is not set.
+func walkHandHistories(baseDir string, fn func(*bufio.Scanner) error) error {
+	handsDir := os.Getenv("DB_HANDS_DIR")
+	if handsDir == "" {
+		return nil
+	}
+	err := filepath.Walk(path.Join(baseDir, handsDir), func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if ok {
-			newTournaments++
+		if info.IsDir() || !strings.HasSuffix(path, ".txt") {
+			return nil
+		}
+		readFile, err := os.Open(path)
+		if err != nil {
+			return err
 		}
+		defer readFile.Close()
+		return fn(bufio.NewScanner(readFile))
+	})
+	if err != nil {
+		return fmt.Errorf("reading hand histories: %w", err)
 	}
-	fmt.Printf("Saved %d tournamets\n", newTournaments)
 	return nil
 }
 
+// setFinished takes the end of a tournament from its last hand or, failing
+// that, from the time the summary file was written.
+func setFinished(t *poker.Tournament, handTimes map[string]time.Time, summary os.FileInfo) {
+	if last, ok := handTimes[t.ID]; ok {
+		t.SetFinished(last)
+		return
+	}
+	// Summary times are wall clock times parsed without a zone.
+	m := summary.ModTime()
+	written := time.Date(m.Year(), m.Month(), m.Day(), m.Hour(), m.Minute(), m.Second(), 0, time.UTC)
+	if written.Sub(t.Started) > maxTournamentDuration {
+		return
+	}
+	t.SetFinished(written)
+}
+
 func (h *hander) Start(ctx context.Context) error {
 	if err := h.ps.Start(ctx); err != nil {
 		return err
 	}
 
+	if err := h.ps.CreatePlayersTables(ctx); err != nil {
+		return err
+	}
 	if err := h.ps.CreateTournamentsTable(ctx); err != nil {
 		return err
 	}
-	return h.parseTournaments(ctx)
+	if err := h.ps.CreateTransactionsTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateRakebackRulesTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateStakingTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateEventRulesTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreatePayoutStructuresTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateRatesTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateAuditTable(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateTagsTables(ctx); err != nil {
+		return err
+	}
+	if err := h.ps.CreateUsersTables(ctx); err != nil {
+		return err
+	}
+	if err := h.ensureAccount(ctx); err != nil {
+		return err
+	}
+	return h.ensureOwner(ctx)
 }
 
 func (h *hander) Stop() {
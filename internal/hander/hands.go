@@ -0,0 +1,221 @@
+package hander
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+// TournamentHands reads the hands of a tournament from the hand histories
+// in DB_HANDS_DIR.
+func (h *hander) TournamentHands(ctx context.Context, id string, account int64) ([]poker.Hand, error) {
+	t, err := h.GetTournament(ctx, id, account)
+	if err != nil {
+		return nil, err
+	}
+	hands, err := readHands(map[string]bool{t.ID: true})
+	if err != nil {
+		return nil, err
+	}
+	if hands[t.ID] == nil {
+		return make([]poker.Hand, 0), nil
+	}
+	return hands[t.ID], nil
+}
+
+// readHands reads the hands of the tournaments from the hand histories in
+// DB_HANDS_DIR, by tournament.
+func readHands(ids map[string]bool) (map[string][]poker.Hand, error) {
+	if os.Getenv("DB_HANDS_DIR") == "" {
+		return nil, ErrNoHands
+	}
+	res := make(map[string][]poker.Hand)
+	err := walkHandHistories(os.Getenv("DB_BASE_DIR"), func(s *bufio.Scanner) error {
+		parsed, err := poker.ParseHands(s)
+		if err != nil {
+			return err
+		}
+		for _, hand := range parsed {
+			if ids[hand.TournamentID] {
+				res[hand.TournamentID] = append(res[hand.TournamentID], hand)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// FinalTableICM values the final table all-ins of a tournament with its
+// payout, stored or estimated.
+func (h *hander) FinalTableICM(ctx context.Context, id string, account int64) ([]stats.AllInICM, error) {
+	hands, err := h.TournamentHands(ctx, id, account)
+	if err != nil {
+		return nil, err
+	}
+	t, err := h.GetTournament(ctx, id, account)
+	if err != nil {
+		return nil, err
+	}
+	payout, err := h.TournamentPayout(ctx, id, account)
+	if err != nil {
+		return nil, err
+	}
+	res, err := stats.FinalTableICM(t, hands, payout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	return res, nil
+}
+
+type (
+	// evKey is a tournament of an account.
+	evKey struct {
+		id      string
+		account int64
+	}
+	// evEntry is a tournament valued with its all-ins. Equity over a whole
+	// history is too slow to compute on every load, so the entries are kept
+	// while the tournament and its payout stay the same and until the next
+	// import brings new hand histories.
+	evEntry struct {
+		// inputs are what the all-ins were valued with besides the hands.
+		inputs string
+		hands  bool
+		ev     stats.TournamentEV
+	}
+)
+
+func evInputs(t poker.Tournament, payout poker.Payout) string {
+	return fmt.Sprint(t.Profit(), t.TotalPrizePool, t.Players, t.MyPlace, t.TableSize(), payout.Prizes)
+}
+
+// TournamentEV evaluates the all-ins of the hero in a tournament against
+// their equity, valued with its payout.
+func (h *hander) TournamentEV(ctx context.Context, id string, account int64) (stats.TournamentEV, error) {
+	t, err := h.GetTournament(ctx, id, account)
+	if err != nil {
+		return stats.TournamentEV{}, err
+	}
+	payout, err := h.TournamentPayout(ctx, id, account)
+	if err != nil {
+		return stats.TournamentEV{}, err
+	}
+	e, ok, err := h.cachedEV(ctx, t, payout)
+	if err != nil || ok {
+		return e.ev, err
+	}
+	hands, err := readHands(map[string]bool{t.ID: true})
+	if err != nil {
+		return stats.TournamentEV{}, err
+	}
+	e, err = h.evaluateEV(ctx, t, hands[t.ID], payout)
+	return e.ev, err
+}
+
+// BankrollEVSeries is the bankroll with the all-in luck taken out of the
+// tournaments found in the hand histories.
+func (h *hander) BankrollEVSeries(ctx context.Context, f Filter, o stats.SeriesOptions) (stats.Series, error) {
+	tournaments, transactions, err := h.bankrollData(ctx, f)
+	if err != nil {
+		return stats.Series{}, err
+	}
+	evs, err := h.tournamentsEV(ctx, tournaments)
+	if err != nil {
+		return stats.Series{}, err
+	}
+	series := stats.BankrollEV(tournaments, transactions, evs, o)
+	series.LedgerOmitted = f.narrowsTournaments()
+	return series, nil
+}
+
+// tournamentsEV evaluates the all-ins of the tournaments with hands. The
+// hand histories are read only for the tournaments not evaluated before.
+func (h *hander) tournamentsEV(ctx context.Context, ts []poker.Tournament) ([]stats.TournamentEV, error) {
+	if os.Getenv("DB_HANDS_DIR") == "" {
+		return nil, ErrNoHands
+	}
+	book, err := h.PayoutBook(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]evEntry, len(ts))
+	payouts := make([]poker.Payout, len(ts))
+	missing := make(map[string]bool)
+	for i, t := range ts {
+		payouts[i] = book.Payout(t)
+		e, ok, err := h.cachedEV(ctx, t, payouts[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			missing[t.ID] = true
+		}
+		entries[i] = e
+	}
+	var hands map[string][]poker.Hand
+	if len(missing) > 0 {
+		if hands, err = readHands(missing); err != nil {
+			return nil, err
+		}
+	}
+	res := make([]stats.TournamentEV, 0)
+	for i, t := range ts {
+		if missing[t.ID] {
+			if entries[i], err = h.evaluateEV(ctx, t, hands[t.ID], payouts[i]); err != nil {
+				return nil, err
+			}
+		}
+		if entries[i].hands {
+			res = append(res, entries[i].ev)
+		}
+	}
+	return res, nil
+}
+
+// cachedEV is the tournament as valued before. One without hands is valued
+// again on the spot, there is nothing to read for it.
+func (h *hander) cachedEV(ctx context.Context, t poker.Tournament, payout poker.Payout) (evEntry, bool, error) {
+	h.evMutex.Lock()
+	e, ok := h.evs[evKey{t.ID, t.AccountID}]
+	h.evMutex.Unlock()
+	switch {
+	case !ok:
+		return evEntry{}, false, nil
+	case e.inputs == evInputs(t, payout):
+		return e, true, nil
+	case !e.hands:
+		e, err := h.evaluateEV(ctx, t, nil, payout)
+		return e, err == nil, err
+	}
+	return evEntry{}, false, nil
+}
+
+// evaluateEV values the all-ins of the tournament and keeps the result.
+func (h *hander) evaluateEV(ctx context.Context, t poker.Tournament, hands []poker.Hand, payout poker.Payout) (evEntry, error) {
+	ev, err := stats.TournamentAllIns(ctx, t, hands, payout)
+	if err != nil {
+		if ctx.Err() != nil {
+			return evEntry{}, err
+		}
+		return evEntry{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	e := evEntry{inputs: evInputs(t, payout), hands: len(hands) > 0, ev: ev}
+	h.evMutex.Lock()
+	h.evs[evKey{t.ID, t.AccountID}] = e
+	h.evMutex.Unlock()
+	return e, nil
+}
+
+// forgetEVs drops the valued tournaments, new hand histories may have come.
+func (h *hander) forgetEVs() {
+	h.evMutex.Lock()
+	h.evs = make(map[evKey]evEntry)
+	h.evMutex.Unlock()
+}
@@ -0,0 +1,86 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/VOVAN1993/poker_hand/internal/persistent"
+	"github.com/VOVAN1993/poker_hand/internal/tracker"
+)
+
+// CSVImport tells where a tracker export goes.
+type CSVImport struct {
+	// Account gets the tournaments, the first account when zero.
+	Account int64
+	// DryRun saves nothing, the report shows what would be imported.
+	DryRun bool
+}
+
+// ImportCSV reads a tracker export and saves the tournaments that are not
+// in the database yet for the account, known ones are left untouched. The
+// tournaments are saved all together or not at all.
+func (h *hander) ImportCSV(ctx context.Context, r io.Reader, p tracker.Profile, o CSVImport) (tracker.Report, error) {
+	roster, err := h.roster(ctx)
+	if err != nil {
+		return tracker.Report{}, err
+	}
+	account, err := roster.defaultAccount()
+	if err != nil {
+		return tracker.Report{}, err
+	}
+	if o.Account != 0 {
+		var ok bool
+		if account, ok = roster.account(o.Account); !ok {
+			return tracker.Report{}, fmt.Errorf("%w: no account #%d", ErrInvalid, o.Account)
+		}
+	}
+	res, err := tracker.Parse(r, p)
+	if err != nil {
+		return tracker.Report{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	ids := make([]string, 0, len(res.Tournaments))
+	for _, t := range res.Tournaments {
+		ids = append(ids, t.ID)
+	}
+	known, err := h.ps.ListTournaments(ctx, persistent.WithIDs(ids...), persistent.WithAccounts(account.ID), persistent.WithDeleted())
+	if err != nil {
+		return tracker.Report{}, err
+	}
+	existing := make(map[string]bool, len(known))
+	for _, t := range known {
+		existing[t.ID] = true
+	}
+
+	report := tracker.Report{
+		DryRun:      o.DryRun,
+		Rows:        res.Rows,
+		Existing:    make([]string, 0, len(known)),
+		Repeated:    res.Repeated,
+		Errors:      res.Errors,
+		Tournaments: res.Tournaments[:0],
+	}
+	for _, t := range res.Tournaments {
+		if existing[t.ID] {
+			report.Existing = append(report.Existing, t.ID)
+			continue
+		}
+		t.AccountID = account.ID
+		if t.Room == "" {
+			t.Room = account.Room
+		}
+		report.Tournaments = append(report.Tournaments, t)
+	}
+	report.New = len(report.Tournaments)
+	if o.DryRun {
+		return report, nil
+	}
+	ts := make([]persistent.Tournament, 0, len(report.Tournaments))
+	for _, t := range report.Tournaments {
+		ts = append(ts, castTournamentToDB(&t))
+	}
+	if _, err := h.ps.SaveNewTournaments(ctx, ts); err != nil {
+		return tracker.Report{}, err
+	}
+	return report, nil
+}
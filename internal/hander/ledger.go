@@ -0,0 +1,103 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+// ListTransactions returns ledger entries in the date range of the filter,
+// of its players if it names any.
+func (h *hander) ListTransactions(ctx context.Context, f Filter) ([]poker.Transaction, error) {
+	transactions, err := h.ps.ListTransactions(ctx, f.ledgerOpts()...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		res = append(res, castTransactionFromDB(&t))
+	}
+	return res, nil
+}
+
+func (h *hander) AddTransaction(ctx context.Context, t poker.Transaction) (poker.Transaction, error) {
+	if t.Currency == "" {
+		t.Currency = poker.USD
+	}
+	if err := t.Validate(); err != nil {
+		return poker.Transaction{}, err
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return poker.Transaction{}, err
+	}
+	if t.PlayerID == 0 {
+		a, err := r.defaultAccount()
+		if err != nil {
+			return poker.Transaction{}, err
+		}
+		t.PlayerID = a.PlayerID
+	}
+	if _, ok := r.players[t.PlayerID]; !ok {
+		return poker.Transaction{}, fmt.Errorf("%w: no player #%d", ErrInvalid, t.PlayerID)
+	}
+	id, err := h.ps.SaveTransaction(ctx, castTransactionToDB(&t))
+	if err != nil {
+		return poker.Transaction{}, err
+	}
+	t.ID = id
+	return t, nil
+}
+
+func (h *hander) DeleteTransaction(ctx context.Context, id int64) error {
+	ok, err := h.ps.DeleteTransaction(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("not found transaction #%d", id)
+	}
+	return nil
+}
+
+// Balance combines tournament results with the ledger.
+func (h *hander) Balance(ctx context.Context, f Filter) (stats.Balance, error) {
+	tournaments, transactions, err := h.bankrollData(ctx, f)
+	if err != nil {
+		return stats.Balance{}, err
+	}
+	b := stats.NewBalance(tournaments, transactions)
+	b.LedgerOmitted = f.narrowsTournaments()
+	return b, nil
+}
+
+func (h *hander) BankrollSeries(ctx context.Context, f Filter, o stats.SeriesOptions) (stats.Series, error) {
+	tournaments, transactions, err := h.bankrollData(ctx, f)
+	if err != nil {
+		return stats.Series{}, err
+	}
+	series := stats.Bankroll(tournaments, transactions, o)
+	series.LedgerOmitted = f.narrowsTournaments()
+	return series, nil
+}
+
+// bankrollData loads tournaments and, unless the filter narrows down the
+// tournaments themselves, the ledger entries in its date range. The ledger
+// belongs to players, so a filter by account leaves it out too; the results
+// tell so with LedgerOmitted.
+func (h *hander) bankrollData(ctx context.Context, f Filter) ([]poker.Tournament, []poker.Transaction, error) {
+	tournaments, err := h.ListTournaments(ctx, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.narrowsTournaments() {
+		return tournaments, nil, nil
+	}
+	transactions, err := h.ListTransactions(ctx, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tournaments, transactions, nil
+}
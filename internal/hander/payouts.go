@@ -0,0 +1,112 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func (h *hander) ListPayoutStructures(ctx context.Context) ([]poker.PayoutStructure, error) {
+	structures, err := h.ps.ListPayoutStructures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.PayoutStructure, 0, len(structures))
+	for _, s := range structures {
+		res = append(res, castPayoutStructureFromDB(&s))
+	}
+	return res, nil
+}
+
+func (h *hander) AddPayoutStructure(ctx context.Context, s poker.PayoutStructure) (poker.PayoutStructure, error) {
+	s.ID = 0
+	if err := checkPayoutStructure(&s); err != nil {
+		return poker.PayoutStructure{}, err
+	}
+	id, err := h.ps.SavePayoutStructure(ctx, castPayoutStructureToDB(&s))
+	if err != nil {
+		return poker.PayoutStructure{}, err
+	}
+	s.ID = id
+	return s, nil
+}
+
+func (h *hander) UpdatePayoutStructure(ctx context.Context, s poker.PayoutStructure) (poker.PayoutStructure, error) {
+	if err := checkPayoutStructure(&s); err != nil {
+		return poker.PayoutStructure{}, err
+	}
+	ok, err := h.ps.UpdatePayoutStructure(ctx, castPayoutStructureToDB(&s))
+	if err != nil {
+		return poker.PayoutStructure{}, err
+	}
+	if !ok {
+		return poker.PayoutStructure{}, fmt.Errorf("%w: payout structure #%d", ErrNotFound, s.ID)
+	}
+	return s, nil
+}
+
+func (h *hander) DeletePayoutStructure(ctx context.Context, id int64) error {
+	ok, err := h.ps.DeletePayoutStructure(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: payout structure #%d", ErrNotFound, id)
+	}
+	return nil
+}
+
+// checkPayoutStructure stores the series of a structure by its key, so it
+// matches however the name was written.
+func checkPayoutStructure(s *poker.PayoutStructure) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Event != "" {
+		s.Event = poker.EventKey(s.Event)
+	}
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	return nil
+}
+
+// PayoutBook holds the stored structures and the series rules to pick them by.
+func (h *hander) PayoutBook(ctx context.Context) (poker.PayoutBook, error) {
+	structures, err := h.ListPayoutStructures(ctx)
+	if err != nil {
+		return poker.PayoutBook{}, err
+	}
+	rules, err := h.ListEventRules(ctx)
+	if err != nil {
+		return poker.PayoutBook{}, err
+	}
+	return poker.PayoutBook{Structures: structures, Events: rules}, nil
+}
+
+// Payouts lists the paid places and min-cash of the filtered tournaments.
+func (h *hander) Payouts(ctx context.Context, f Filter) ([]stats.TournamentPayout, error) {
+	ts, err := h.ListTournaments(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	book, err := h.PayoutBook(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Payouts(ts, book), nil
+}
+
+// TournamentPayout is the payout table of one tournament.
+func (h *hander) TournamentPayout(ctx context.Context, id string, account int64) (poker.Payout, error) {
+	t, err := h.GetTournament(ctx, id, account)
+	if err != nil {
+		return poker.Payout{}, err
+	}
+	book, err := h.PayoutBook(ctx)
+	if err != nil {
+		return poker.Payout{}, err
+	}
+	return book.Payout(t), nil
+}
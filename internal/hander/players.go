@@ -0,0 +1,247 @@
+package hander
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+const (
+	// defaultPlayer names the player created for an instance without any.
+	defaultPlayer = "Hero"
+	// defaultScreenName is how the summaries call the player.
+	defaultScreenName = "Hero"
+)
+
+type (
+	// roster holds the players and accounts to resolve tournaments against.
+	roster struct {
+		players  map[int64]poker.Player
+		accounts []poker.Account
+	}
+)
+
+func (h *hander) ListPlayers(ctx context.Context) ([]poker.Player, error) {
+	players, err := h.ps.ListPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Player, 0, len(players))
+	for _, p := range players {
+		res = append(res, castPlayerFromDB(&p))
+	}
+	return res, nil
+}
+
+func (h *hander) AddPlayer(ctx context.Context, p poker.Player) (poker.Player, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if err := p.Validate(); err != nil {
+		return poker.Player{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	if err := h.checkPlayerName(ctx, p); err != nil {
+		return poker.Player{}, err
+	}
+	id, err := h.ps.SavePlayer(ctx, castPlayerToDB(&p))
+	if err != nil {
+		return poker.Player{}, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+// UpdatePlayer renames a player.
+func (h *hander) UpdatePlayer(ctx context.Context, p poker.Player) (poker.Player, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if err := p.Validate(); err != nil {
+		return poker.Player{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	if err := h.checkPlayerName(ctx, p); err != nil {
+		return poker.Player{}, err
+	}
+	ok, err := h.ps.UpdatePlayer(ctx, castPlayerToDB(&p))
+	if err != nil {
+		return poker.Player{}, err
+	}
+	if !ok {
+		return poker.Player{}, fmt.Errorf("%w: player #%d", ErrNotFound, p.ID)
+	}
+	return p, nil
+}
+
+func (h *hander) checkPlayerName(ctx context.Context, p poker.Player) error {
+	players, err := h.ListPlayers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range players {
+		if other.ID != p.ID && strings.EqualFold(other.Name, p.Name) {
+			return fmt.Errorf("%w: player %q", ErrExists, p.Name)
+		}
+	}
+	return nil
+}
+
+func (h *hander) ListAccounts(ctx context.Context) ([]poker.Account, error) {
+	accounts, err := h.ps.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Account, 0, len(accounts))
+	for _, a := range accounts {
+		res = append(res, castAccountFromDB(&a))
+	}
+	return res, nil
+}
+
+func (h *hander) AddAccount(ctx context.Context, a poker.Account) (poker.Account, error) {
+	if err := h.checkAccount(ctx, &a); err != nil {
+		return poker.Account{}, err
+	}
+	id, err := h.ps.SaveAccount(ctx, castAccountToDB(&a))
+	if err != nil {
+		return poker.Account{}, err
+	}
+	a.ID = id
+	return a, nil
+}
+
+// UpdateAccount moves an account to another player or changes where its summaries are.
+func (h *hander) UpdateAccount(ctx context.Context, a poker.Account) (poker.Account, error) {
+	if err := h.checkAccount(ctx, &a); err != nil {
+		return poker.Account{}, err
+	}
+	ok, err := h.ps.UpdateAccount(ctx, castAccountToDB(&a))
+	if err != nil {
+		return poker.Account{}, err
+	}
+	if !ok {
+		return poker.Account{}, fmt.Errorf("%w: account #%d", ErrNotFound, a.ID)
+	}
+	return a, nil
+}
+
+func (h *hander) checkAccount(ctx context.Context, a *poker.Account) error {
+	a.Room, a.ScreenName = strings.TrimSpace(a.Room), strings.TrimSpace(a.ScreenName)
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.players[a.PlayerID]; !ok {
+		return fmt.Errorf("%w: no player #%d", ErrInvalid, a.PlayerID)
+	}
+	for _, other := range r.accounts {
+		if other.ID != a.ID && other.Matches(a.Room, a.ScreenName) {
+			return fmt.Errorf("%w: account %s", ErrExists, a)
+		}
+	}
+	return nil
+}
+
+func (h *hander) roster(ctx context.Context) (roster, error) {
+	players, err := h.ListPlayers(ctx)
+	if err != nil {
+		return roster{}, err
+	}
+	accounts, err := h.ListAccounts(ctx)
+	if err != nil {
+		return roster{}, err
+	}
+	r := roster{players: make(map[int64]poker.Player, len(players)), accounts: accounts}
+	for _, p := range players {
+		r.players[p.ID] = p
+	}
+	return r, nil
+}
+
+func (r roster) account(id int64) (poker.Account, bool) {
+	for _, a := range r.accounts {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return poker.Account{}, false
+}
+
+// byScreenName is the first account with the screen name. Summaries do not
+// name the room, so the accounts of every room are looked at.
+func (r roster) byScreenName(screenName string) (poker.Account, bool) {
+	for _, a := range r.accounts {
+		if a.Matches(a.Room, screenName) {
+			return a, true
+		}
+	}
+	return poker.Account{}, false
+}
+
+func (r roster) player(name string) (poker.Player, bool) {
+	for _, p := range r.players {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return poker.Player{}, false
+}
+
+// fill sets the player and the account label of a tournament.
+func (r roster) fill(t *poker.Tournament) {
+	a, ok := r.account(t.AccountID)
+	if !ok {
+		return
+	}
+	t.PlayerID = a.PlayerID
+	t.Player = r.players[a.PlayerID].Name
+	t.Account = a.String()
+}
+
+// defaultAccount is the first account, it gets the data nobody claims.
+func (r roster) defaultAccount() (poker.Account, error) {
+	if len(r.accounts) == 0 {
+		return poker.Account{}, errors.New("no accounts, the manager is not started")
+	}
+	return r.accounts[0], nil
+}
+
+// ensureAccount creates the first player and account and hands them the
+// data saved before accounts existed. DB_PLAYER names the player, DB_ROOM
+// and DB_TOURNAMENT_DIR describe the account.
+func (h *hander) ensureAccount(ctx context.Context) error {
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	if len(r.accounts) == 0 {
+		name := os.Getenv("DB_PLAYER")
+		if name == "" {
+			name = defaultPlayer
+		}
+		p, ok := r.player(name)
+		if !ok {
+			if p, err = h.AddPlayer(ctx, poker.Player{Name: name}); err != nil {
+				return err
+			}
+		}
+		room := os.Getenv("DB_ROOM")
+		if room == "" {
+			room = defaultRoom
+		}
+		a := poker.Account{PlayerID: p.ID, Room: room, ScreenName: defaultScreenName, SourceDir: os.Getenv("DB_TOURNAMENT_DIR")}
+		if _, err := h.AddAccount(ctx, a); err != nil {
+			return err
+		}
+		if r, err = h.roster(ctx); err != nil {
+			return err
+		}
+	}
+	a, err := r.defaultAccount()
+	if err != nil {
+		return err
+	}
+	return h.ps.AdoptOrphans(ctx, castAccountToDB(&a))
+}
@@ -0,0 +1,61 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func (h *hander) ListRakebackRules(ctx context.Context) ([]poker.RakebackRule, error) {
+	rules, err := h.ps.ListRakebackRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.RakebackRule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, castRakebackRuleFromDB(&r))
+	}
+	return res, nil
+}
+
+func (h *hander) AddRakebackRule(ctx context.Context, r poker.RakebackRule) (poker.RakebackRule, error) {
+	if err := r.Validate(); err != nil {
+		return poker.RakebackRule{}, err
+	}
+	id, err := h.ps.SaveRakebackRule(ctx, castRakebackRuleToDB(&r))
+	if err != nil {
+		return poker.RakebackRule{}, err
+	}
+	r.ID = id
+	return r, nil
+}
+
+func (h *hander) DeleteRakebackRule(ctx context.Context, id int64) error {
+	ok, err := h.ps.DeleteRakebackRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("not found rakeback rule #%d", id)
+	}
+	return nil
+}
+
+// Rakeback reconciles the rakeback expected from every rule with the ledger.
+func (h *hander) Rakeback(ctx context.Context, f Filter) ([]stats.RakebackMonth, error) {
+	tournaments, err := h.ListTournaments(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := h.ListTransactions(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := h.ListRakebackRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Rakeback(tournaments, transactions, rules), nil
+}
@@ -0,0 +1,118 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func (h *hander) ListDeals(ctx context.Context) ([]poker.Deal, error) {
+	deals, err := h.ps.ListDeals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Deal, 0, len(deals))
+	for _, d := range deals {
+		res = append(res, castDealFromDB(&d))
+	}
+	return res, nil
+}
+
+func (h *hander) GetDeal(ctx context.Context, id int64) (poker.Deal, error) {
+	deals, err := h.ListDeals(ctx)
+	if err != nil {
+		return poker.Deal{}, err
+	}
+	for _, d := range deals {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return poker.Deal{}, fmt.Errorf("%w: deal #%d", ErrNotFound, id)
+}
+
+func (h *hander) AddDeal(ctx context.Context, d poker.Deal) (poker.Deal, error) {
+	d.ID = 0
+	if err := h.checkDeal(ctx, &d); err != nil {
+		return poker.Deal{}, err
+	}
+	id, err := h.ps.SaveDeal(ctx, castDealToDB(&d))
+	if err != nil {
+		return poker.Deal{}, err
+	}
+	d.ID = id
+	return d, nil
+}
+
+func (h *hander) UpdateDeal(ctx context.Context, d poker.Deal) (poker.Deal, error) {
+	if err := h.checkDeal(ctx, &d); err != nil {
+		return poker.Deal{}, err
+	}
+	ok, err := h.ps.UpdateDeal(ctx, castDealToDB(&d))
+	if err != nil {
+		return poker.Deal{}, err
+	}
+	if !ok {
+		return poker.Deal{}, fmt.Errorf("%w: deal #%d", ErrNotFound, d.ID)
+	}
+	return d, nil
+}
+
+func (h *hander) DeleteDeal(ctx context.Context, id int64) error {
+	ok, err := h.ps.DeleteDeal(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: deal #%d", ErrNotFound, id)
+	}
+	return nil
+}
+
+// checkDeal refuses deals of unknown players and selling more than all of
+// the action to the deals running at the same time.
+func (h *hander) checkDeal(ctx context.Context, d *poker.Deal) error {
+	d.Backer, d.Note = strings.TrimSpace(d.Backer), strings.TrimSpace(d.Note)
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.players[d.PlayerID]; !ok {
+		return fmt.Errorf("%w: no player #%d", ErrInvalid, d.PlayerID)
+	}
+	deals, err := h.ListDeals(ctx)
+	if err != nil {
+		return err
+	}
+	sold := d.Sold
+	for _, other := range deals {
+		if other.ID != d.ID && other.PlayerID == d.PlayerID && other.Overlaps(*d) {
+			sold += other.Sold
+		}
+	}
+	if sold > 100 {
+		return fmt.Errorf("%w: %.4g%% of the action would be sold at once", ErrInvalid, sold)
+	}
+	return nil
+}
+
+// DealReport splits the tournaments of a deal between the player and the
+// backer and settles it per period.
+func (h *hander) DealReport(ctx context.Context, id int64) (stats.StakingReport, error) {
+	d, err := h.GetDeal(ctx, id)
+	if err != nil {
+		return stats.StakingReport{}, err
+	}
+	ts, err := h.ListTournaments(ctx, Filter{From: d.Start, To: d.End, Players: []int64{d.PlayerID}})
+	if err != nil {
+		return stats.StakingReport{}, err
+	}
+	return stats.Staking(d, ts, time.Now().UTC()), nil
+}
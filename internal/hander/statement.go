@@ -0,0 +1,51 @@
+package hander
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func (h *hander) ListRates(ctx context.Context) ([]poker.Rate, error) {
+	rates, err := h.ps.ListRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Rate, 0, len(rates))
+	for _, r := range rates {
+		res = append(res, castRateFromDB(&r))
+	}
+	return res, nil
+}
+
+func (h *hander) SaveRates(ctx context.Context, rates []poker.Rate) error {
+	for _, r := range rates {
+		if _, err := poker.ParseCurrency(string(r.Currency)); err != nil {
+			return err
+		}
+		if r.USD <= 0 || r.Date.IsZero() {
+			return errors.New("rate needs a date and a positive usd value")
+		}
+		if err := h.ps.SaveRate(ctx, castRateToDB(&r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Statement builds the yearly profit statement in the given currency.
+func (h *hander) Statement(ctx context.Context, year int, currency poker.Currency) (stats.Statement, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tournaments, err := h.ListTournaments(ctx, Filter{From: from, To: from.AddDate(1, 0, 0)})
+	if err != nil {
+		return stats.Statement{}, err
+	}
+	rates, err := h.ListRates(ctx)
+	if err != nil {
+		return stats.Statement{}, err
+	}
+	return stats.NewStatement(tournaments, year, currency, poker.NewRates(rates)), nil
+}
@@ -0,0 +1,141 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/persistent"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func (h *hander) AddTag(ctx context.Context, tournamentID string, account int64, tag string) error {
+	tag, err := poker.NormalizeTag(tag)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	t, err := h.getTournament(ctx, tournamentID, account)
+	if err != nil {
+		return err
+	}
+	_, err = h.ps.AddTag(ctx, t.ID, t.AccountID, tag)
+	return err
+}
+
+func (h *hander) RemoveTag(ctx context.Context, tournamentID string, account int64, tag string) error {
+	tag, err := poker.NormalizeTag(tag)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	t, err := h.getTournament(ctx, tournamentID, account, persistent.WithDeleted())
+	if err != nil {
+		return err
+	}
+	ok, err := h.ps.RemoveTag(ctx, t.ID, t.AccountID, tag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: tag %q of tournament #%s", ErrNotFound, tag, tournamentID)
+	}
+	return nil
+}
+
+// ListTags counts the tags of the tournaments of the filter.
+func (h *hander) ListTags(ctx context.Context, f Filter) ([]poker.TagUsage, error) {
+	tags, err := h.ps.ListTags(ctx, f.whereOpts()...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.TagUsage, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, poker.TagUsage{Tag: t.Tag, Tournaments: t.Tournaments})
+	}
+	return res, nil
+}
+
+// ListNotes returns the notes of a tournament, of all of them for an empty id.
+func (h *hander) ListNotes(ctx context.Context, tournamentID string, account int64) ([]poker.Note, error) {
+	if tournamentID != "" {
+		t, err := h.getTournament(ctx, tournamentID, account, persistent.WithDeleted())
+		if err != nil {
+			return nil, err
+		}
+		account = t.AccountID
+	}
+	notes, err := h.ps.ListNotes(ctx, tournamentID, account)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.Note, 0, len(notes))
+	for _, n := range notes {
+		res = append(res, castNoteFromDB(&n))
+	}
+	return res, nil
+}
+
+func (h *hander) AddNote(ctx context.Context, n poker.Note) (poker.Note, error) {
+	if err := n.Validate(); err != nil {
+		return poker.Note{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	t, err := h.getTournament(ctx, n.TournamentID, n.AccountID)
+	if err != nil {
+		return poker.Note{}, err
+	}
+	n.AccountID = t.AccountID
+	n.Created = time.Now().UTC()
+	n.Updated = n.Created
+	id, err := h.ps.SaveNote(ctx, castNoteToDB(&n))
+	if err != nil {
+		return poker.Note{}, err
+	}
+	n.ID = id
+	return n, nil
+}
+
+// UpdateNote replaces the text of a note.
+func (h *hander) UpdateNote(ctx context.Context, n poker.Note) (poker.Note, error) {
+	if err := n.Validate(); err != nil {
+		return poker.Note{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	t, err := h.getTournament(ctx, n.TournamentID, n.AccountID, persistent.WithDeleted())
+	if err != nil {
+		return poker.Note{}, err
+	}
+	n.AccountID = t.AccountID
+	n.Updated = time.Now().UTC()
+	ok, err := h.ps.UpdateNote(ctx, castNoteToDB(&n))
+	if err != nil {
+		return poker.Note{}, err
+	}
+	if !ok {
+		return poker.Note{}, fmt.Errorf("%w: note %d of tournament #%s", ErrNotFound, n.ID, n.TournamentID)
+	}
+	notes, err := h.ListNotes(ctx, n.TournamentID, n.AccountID)
+	if err != nil {
+		return poker.Note{}, err
+	}
+	for _, saved := range notes {
+		if saved.ID == n.ID {
+			return saved, nil
+		}
+	}
+	return n, nil
+}
+
+// DeleteNote removes a note, only its id, tournament and account are used.
+func (h *hander) DeleteNote(ctx context.Context, n poker.Note) error {
+	t, err := h.getTournament(ctx, n.TournamentID, n.AccountID, persistent.WithDeleted())
+	if err != nil {
+		return err
+	}
+	n.AccountID = t.AccountID
+	ok, err := h.ps.DeleteNote(ctx, castNoteToDB(&n))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: note %d of tournament #%s", ErrNotFound, n.ID, n.TournamentID)
+	}
+	return nil
+}
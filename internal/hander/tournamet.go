@@ -3,44 +3,200 @@ package hander
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/VOVAN1993/poker_hand/internal/persistent"
 	"github.com/VOVAN1993/poker_hand/internal/poker"
 )
 
-func (h *hander) GetTournament(ctx context.Context, id string) (poker.Tournament, error) {
-	tournaments, err := h.ps.ListTournaments(ctx, []persistent.WhereOpt{persistent.WithID(id)}...)
+// GetTournament finds a tournament by its id, account 0 stands for the
+// only account that played it.
+func (h *hander) GetTournament(ctx context.Context, id string, account int64) (poker.Tournament, error) {
+	return h.getTournament(ctx, id, account)
+}
+
+func (h *hander) getTournament(ctx context.Context, id string, account int64, opts ...persistent.WhereOpt) (poker.Tournament, error) {
+	opts = append(opts, persistent.WithID(id))
+	if account != 0 {
+		opts = append(opts, persistent.WithAccounts(account))
+	}
+	tournaments, err := h.ps.ListTournaments(ctx, opts...)
 	if err != nil {
 		return poker.Tournament{}, err
 	}
 	if len(tournaments) == 0 {
-		return poker.Tournament{}, fmt.Errorf("not found tournament #%s", id)
+		return poker.Tournament{}, fmt.Errorf("%w: tournament #%s", ErrNotFound, id)
 	}
 	if len(tournaments) > 1 {
-		return poker.Tournament{}, fmt.Errorf("found some tournaments with id #%s", id)
+		return poker.Tournament{}, fmt.Errorf("%w: tournament #%s was played by %d accounts, pick one", ErrInvalid, id, len(tournaments))
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return poker.Tournament{}, err
 	}
-	return castTournamentFromDB(&tournaments[0]), nil
+	t := castTournamentFromDB(&tournaments[0])
+	r.fill(&t)
+	return t, nil
 }
 
-func (h *hander) ListTournaments(ctx context.Context) ([]poker.Tournament, error) {
-	tournaments, err := h.ps.ListTournaments(ctx)
+func (h *hander) ListTournaments(ctx context.Context, f Filter) ([]poker.Tournament, error) {
+	r, err := h.roster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tournaments, err := h.ps.ListTournaments(ctx, f.whereOpts()...)
 	if err != nil {
 		return nil, err
 	}
 	res := make([]poker.Tournament, 0, len(tournaments))
 	for _, t := range tournaments {
 		res = append(res, castTournamentFromDB(&t))
+		r.fill(&res[len(res)-1])
+	}
+	return res, nil
+}
+
+// IterateTournaments streams the filtered tournaments in start order.
+func (h *hander) IterateTournaments(ctx context.Context, f Filter, fn func(poker.Tournament) error) error {
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	return h.ps.IterateTournaments(ctx, func(t persistent.Tournament) error {
+		res := castTournamentFromDB(&t)
+		r.fill(&res)
+		return fn(res)
+	}, f.whereOpts()...)
+}
+
+func (h *hander) FreeTournament(ctx context.Context, id string, account int64) error {
+	free := true
+	_, err := h.UpdateTournament(ctx, id, account, poker.TournamentPatch{Free: &free})
+	return err
+}
+
+// AddTournament saves a tournament missing from the summaries, to the
+// first account unless it names one.
+func (h *hander) AddTournament(ctx context.Context, t poker.Tournament) (poker.Tournament, error) {
+	r, err := h.roster(ctx)
+	if err != nil {
+		return poker.Tournament{}, err
+	}
+	if t.AccountID == 0 {
+		a, err := r.defaultAccount()
+		if err != nil {
+			return poker.Tournament{}, err
+		}
+		t.AccountID = a.ID
+	}
+	a, ok := r.account(t.AccountID)
+	if !ok {
+		return poker.Tournament{}, fmt.Errorf("%w: no account #%d", ErrInvalid, t.AccountID)
+	}
+	if t.Room == "" {
+		t.Room = a.Room
+	}
+	if t.Currency == "" {
+		t.Currency = poker.USD
+	}
+	if t.Type == "" {
+		t.Type, _ = poker.TypeByName(t.Name)
+	}
+	if err := t.Validate(); err != nil {
+		return poker.Tournament{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	t.Edited, t.Deleted = true, time.Time{}
+	_, changes := poker.PatchOf(t).Apply(poker.Tournament{ID: t.ID, AccountID: t.AccountID})
+	for i := range changes {
+		changes[i].Old = nil
+	}
+	ok, err = h.ps.InsertTournament(ctx, castTournamentToDB(&t), h.auditEntry(t, poker.AuditCreate, changes))
+	if err != nil {
+		return poker.Tournament{}, err
+	}
+	if !ok {
+		return poker.Tournament{}, fmt.Errorf("%w: tournament #%s", ErrExists, t.ID)
+	}
+	r.fill(&t)
+	return t, nil
+}
+
+// UpdateTournament corrects a tournament by hand, recording what changed.
+func (h *hander) UpdateTournament(ctx context.Context, id string, account int64, p poker.TournamentPatch) (poker.Tournament, error) {
+	t, err := h.getTournament(ctx, id, account)
+	if err != nil {
+		return poker.Tournament{}, err
+	}
+	updated, changes := p.Apply(t)
+	if len(changes) == 0 {
+		return t, nil
+	}
+	if err := updated.Validate(); err != nil {
+		return poker.Tournament{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	updated.Edited = true
+	if err := h.updateTournament(ctx, updated, poker.AuditUpdate, changes); err != nil {
+		return poker.Tournament{}, err
+	}
+	return updated, nil
+}
+
+// DeleteTournament hides a tournament from every report, it can be undeleted.
+func (h *hander) DeleteTournament(ctx context.Context, id string, account int64) error {
+	t, err := h.getTournament(ctx, id, account)
+	if err != nil {
+		return err
+	}
+	t.Deleted = time.Now().UTC()
+	changes := []poker.FieldChange{{Field: "deleted", Old: nil, New: t.Deleted}}
+	return h.updateTournament(ctx, t, poker.AuditDelete, changes)
+}
+
+func (h *hander) UndeleteTournament(ctx context.Context, id string, account int64) error {
+	t, err := h.getTournament(ctx, id, account, persistent.WithDeleted())
+	if err != nil {
+		return err
+	}
+	if t.Deleted.IsZero() {
+		return nil
+	}
+	changes := []poker.FieldChange{{Field: "deleted", Old: t.Deleted, New: nil}}
+	t.Deleted = time.Time{}
+	return h.updateTournament(ctx, t, poker.AuditUndelete, changes)
+}
+
+// TournamentAudit lists the manual changes of a tournament, of all tournaments for an empty id.
+func (h *hander) TournamentAudit(ctx context.Context, id string, account int64) ([]poker.AuditEntry, error) {
+	if id != "" {
+		t, err := h.getTournament(ctx, id, account, persistent.WithDeleted())
+		if err != nil {
+			return nil, err
+		}
+		account = t.AccountID
+	}
+	entries, err := h.ps.ListAudit(ctx, id, account)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]poker.AuditEntry, 0, len(entries))
+	for _, a := range entries {
+		res = append(res, castAuditEntryFromDB(&a))
 	}
 	return res, nil
 }
 
-func (h *hander) FreeTournament(ctx context.Context, id string) error {
-	ok, err := h.ps.FreeTournament(ctx, id)
+func (h *hander) updateTournament(ctx context.Context, t poker.Tournament, action poker.AuditAction, changes []poker.FieldChange) error {
+	ok, err := h.ps.UpdateTournament(ctx, castTournamentToDB(&t), h.auditEntry(t, action, changes))
 	if err != nil {
 		return err
 	}
 	if !ok {
-		return fmt.Errorf("not found tournament #%s", id)
+		return fmt.Errorf("%w: tournament #%s", ErrNotFound, t.ID)
 	}
 	return nil
 }
+
+func (h *hander) auditEntry(t poker.Tournament, action poker.AuditAction, changes []poker.FieldChange) persistent.AuditEntry {
+	a := poker.AuditEntry{TournamentID: t.ID, AccountID: t.AccountID, Action: action, Changes: changes, At: time.Now().UTC()}
+	return castAuditEntryToDB(&a)
+}
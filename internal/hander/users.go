@@ -0,0 +1,284 @@
+package hander
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/auth"
+)
+
+const (
+	// sessionTTL is how long a login on the web pages lasts.
+	sessionTTL = 30 * 24 * time.Hour
+	// defaultOwner names the owner created for an instance without users.
+	defaultOwner = "owner"
+	// dummyPasswordHash is checked for an unknown user name, so that the
+	// time of the answer does not tell which names exist.
+	dummyPasswordHash = "$2a$10$eaLu0WRH1CLEKIKpD4202unlkyFpi1Wow7l/72y3H25BBUvBETPgC"
+)
+
+// Login checks the password and opens a session, its secret goes to the cookie.
+func (h *hander) Login(ctx context.Context, name, password string) (string, auth.User, error) {
+	users, err := h.ps.ListUsers(ctx)
+	if err != nil {
+		return "", auth.User{}, err
+	}
+	for _, u := range users {
+		if u.Name != name {
+			continue
+		}
+		if !auth.CheckPassword(u.PasswordHash, password) {
+			return "", auth.User{}, fmt.Errorf("%w: wrong user name or password", ErrUnauthorized)
+		}
+		user := castUserFromDB(&u)
+		_, secret, err := h.createToken(ctx, auth.Token{UserID: u.ID, Name: "session", Session: true}, sessionTTL)
+		if err != nil {
+			return "", auth.User{}, err
+		}
+		return secret, user, nil
+	}
+	auth.CheckPassword(dummyPasswordHash, password)
+	return "", auth.User{}, fmt.Errorf("%w: wrong user name or password", ErrUnauthorized)
+}
+
+// Logout closes the session or revokes the token with the secret.
+func (h *hander) Logout(ctx context.Context, secret string) error {
+	t, ok, err := h.ps.FindToken(ctx, auth.HashSecret(secret))
+	if err != nil || !ok {
+		return err
+	}
+	_, err = h.ps.DeleteToken(ctx, t.UserID, t.ID)
+	return err
+}
+
+// Authenticate finds the user of a token or session secret.
+func (h *hander) Authenticate(ctx context.Context, secret string) (auth.User, error) {
+	if secret == "" {
+		return auth.User{}, fmt.Errorf("%w: no token", ErrUnauthorized)
+	}
+	t, ok, err := h.ps.FindToken(ctx, auth.HashSecret(secret))
+	if err != nil {
+		return auth.User{}, err
+	}
+	now := time.Now().UTC()
+	if !ok || castTokenFromDB(&t).Expired(now) {
+		return auth.User{}, fmt.Errorf("%w: unknown or expired token", ErrUnauthorized)
+	}
+	u, err := h.getUser(ctx, t.UserID)
+	if err != nil {
+		return auth.User{}, err
+	}
+	if err := h.ps.TouchToken(ctx, t.ID, now); err != nil {
+		return auth.User{}, err
+	}
+	return u, nil
+}
+
+func (h *hander) ListUsers(ctx context.Context) ([]auth.User, error) {
+	users, err := h.ps.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]auth.User, 0, len(users))
+	for _, u := range users {
+		res = append(res, castUserFromDB(&u))
+	}
+	return res, nil
+}
+
+func (h *hander) getUser(ctx context.Context, id int64) (auth.User, error) {
+	users, err := h.ListUsers(ctx)
+	if err != nil {
+		return auth.User{}, err
+	}
+	for _, u := range users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return auth.User{}, fmt.Errorf("%w: user #%d", ErrNotFound, id)
+}
+
+func (h *hander) AddUser(ctx context.Context, u auth.User, password string) (auth.User, error) {
+	u.ID = 0
+	if err := h.checkUser(ctx, &u); err != nil {
+		return auth.User{}, err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return auth.User{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	id, err := h.ps.SaveUser(ctx, castUserToDB(&u, hash))
+	if err != nil {
+		return auth.User{}, err
+	}
+	u.ID = id
+	return u, nil
+}
+
+// UpdateUser changes the role and players of a user, and the password
+// unless it is empty.
+func (h *hander) UpdateUser(ctx context.Context, u auth.User, password string) (auth.User, error) {
+	if err := h.checkUser(ctx, &u); err != nil {
+		return auth.User{}, err
+	}
+	var hash string
+	if password != "" {
+		var err error
+		if hash, err = auth.HashPassword(password); err != nil {
+			return auth.User{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+		}
+	}
+	if u.Role != auth.RoleOwner {
+		if err := h.keepOwner(ctx, u.ID); err != nil {
+			return auth.User{}, err
+		}
+	}
+	ok, err := h.ps.UpdateUser(ctx, castUserToDB(&u, hash))
+	if err != nil {
+		return auth.User{}, err
+	}
+	if !ok {
+		return auth.User{}, fmt.Errorf("%w: user #%d", ErrNotFound, u.ID)
+	}
+	return u, nil
+}
+
+func (h *hander) DeleteUser(ctx context.Context, id int64) error {
+	if err := h.keepOwner(ctx, id); err != nil {
+		return err
+	}
+	ok, err := h.ps.DeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: user #%d", ErrNotFound, id)
+	}
+	return nil
+}
+
+func (h *hander) checkUser(ctx context.Context, u *auth.User) error {
+	u.Name = strings.TrimSpace(u.Name)
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	users, err := h.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range users {
+		if other.ID != u.ID && other.Name == u.Name {
+			return fmt.Errorf("%w: user %q", ErrExists, u.Name)
+		}
+	}
+	r, err := h.roster(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range u.Players {
+		if _, ok := r.players[id]; !ok {
+			return fmt.Errorf("%w: no player #%d", ErrInvalid, id)
+		}
+	}
+	return nil
+}
+
+// keepOwner refuses to leave the instance without an owner when the user
+// is removed or loses the role.
+func (h *hander) keepOwner(ctx context.Context, id int64) error {
+	users, err := h.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if u.ID != id && u.Role == auth.RoleOwner {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the last owner cannot be removed", ErrInvalid)
+}
+
+// CreateToken issues an API token, the secret is returned only here. A zero
+// ttl makes a token that never expires.
+func (h *hander) CreateToken(ctx context.Context, userID int64, name string, ttl time.Duration) (auth.Token, string, error) {
+	if _, err := h.getUser(ctx, userID); err != nil {
+		return auth.Token{}, "", err
+	}
+	if ttl < 0 {
+		return auth.Token{}, "", fmt.Errorf("%w: negative token lifetime", ErrInvalid)
+	}
+	return h.createToken(ctx, auth.Token{UserID: userID, Name: strings.TrimSpace(name)}, ttl)
+}
+
+func (h *hander) createToken(ctx context.Context, t auth.Token, ttl time.Duration) (auth.Token, string, error) {
+	secret, err := auth.NewSecret()
+	if err != nil {
+		return auth.Token{}, "", err
+	}
+	t.Hash = auth.HashSecret(secret)
+	t.Created = time.Now().UTC()
+	if ttl > 0 {
+		t.Expires = t.Created.Add(ttl)
+	}
+	id, err := h.ps.SaveToken(ctx, castTokenToDB(&t))
+	if err != nil {
+		return auth.Token{}, "", err
+	}
+	t.ID = id
+	return t, secret, nil
+}
+
+func (h *hander) ListTokens(ctx context.Context, userID int64) ([]auth.Token, error) {
+	tokens, err := h.ps.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]auth.Token, 0, len(tokens))
+	for _, t := range tokens {
+		res = append(res, castTokenFromDB(&t))
+	}
+	return res, nil
+}
+
+func (h *hander) DeleteToken(ctx context.Context, userID, id int64) error {
+	ok, err := h.ps.DeleteToken(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: token #%d", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ensureOwner creates the first owner of an instance without users from
+// AUTH_OWNER and AUTH_PASSWORD. Without a password a random one is printed
+// once. Expired sessions are cleaned up on the way.
+func (h *hander) ensureOwner(ctx context.Context) error {
+	if err := h.ps.DeleteExpiredTokens(ctx, time.Now().UTC()); err != nil {
+		return err
+	}
+	users, err := h.ps.ListUsers(ctx)
+	if err != nil || len(users) > 0 {
+		return err
+	}
+	name := os.Getenv("AUTH_OWNER")
+	if name == "" {
+		name = defaultOwner
+	}
+	password := os.Getenv("AUTH_PASSWORD")
+	if password == "" {
+		secret, err := auth.NewSecret()
+		if err != nil {
+			return err
+		}
+		password = secret[:20]
+		fmt.Printf("Created owner %q with password %s, change it with the user command\n", name, password)
+	}
+	_, err = h.AddUser(ctx, auth.User{Name: name, Role: auth.RoleOwner}, password)
+	return err
+}
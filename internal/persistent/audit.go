@@ -0,0 +1,128 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *db) CreateAuditTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS tournament_audit (
+		id BIGSERIAL PRIMARY KEY,
+		tournament_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		changes JSONB NOT NULL DEFAULT '[]',
+		at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS tournament_audit_tournament_id ON tournament_audit (tournament_id);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create tournament_audit table: %w", err)
+	}
+	return db.migrate(ctx, "tournament_audit", auditMigrations)
+}
+
+// ListAudit returns the changes of a tournament, of all of them when the id is empty.
+func (db *db) ListAudit(ctx context.Context, tournamentID string, accountID int64) ([]AuditEntry, error) {
+	query := `SELECT id, tournament_id, account_id, action, changes, at FROM tournament_audit`
+	var args []any
+	if tournamentID != "" {
+		query += ` WHERE tournament_id = $1 AND account_id = $2`
+		args = append(args, tournamentID, accountID)
+	}
+	rows, err := db.pool.Query(ctx, query+` ORDER BY at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var a AuditEntry
+		if err := rows.Scan(&a.ID, &a.TournamentID, &a.AccountID, &a.Action, &a.Changes, &a.At); err != nil {
+			return nil, err
+		}
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
+
+// InsertTournament adds a tournament entered by hand and its audit entry.
+// It reports false when the id is already taken.
+func (db *db) InsertTournament(ctx context.Context, t Tournament, a AuditEntry) (bool, error) {
+	query := `
+	INSERT INTO tournaments (
+		id, bi, bi_rake, bi_bounty, players, total_prize_pool, started, finished, duration,
+		my_place, my_prize, reentries, name, type, free, currency, room, account_id, edited
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, TRUE
+	) ON CONFLICT (id, account_id) DO NOTHING;`
+
+	return db.withAudit(ctx, a, func(tx pgx.Tx) (bool, error) {
+		st, err := tx.Exec(ctx, query,
+			t.ID, t.BI, t.BIRake, t.BIBounty, t.Players, t.TotalPrizePool, t.Started, t.Finished, durationSeconds(t.Duration),
+			t.MyPlace, t.MyPrize, t.Reentries, t.Name, t.Type, t.Free, t.Currency, t.Room, t.AccountID,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert tournament: %w", err)
+		}
+		return st.RowsAffected() == 1, nil
+	})
+}
+
+// UpdateTournament overwrites a tournament, deleted or not, marks it as
+// edited and records the audit entry. It reports false for an unknown id.
+func (db *db) UpdateTournament(ctx context.Context, t Tournament, a AuditEntry) (bool, error) {
+	query := `
+	UPDATE tournaments SET
+		bi = $2, bi_rake = $3, bi_bounty = $4, players = $5, total_prize_pool = $6,
+		started = $7, finished = $8, duration = $9, my_place = $10, my_prize = $11,
+		reentries = $12, name = $13, type = $14, free = $15, currency = $16, room = $17,
+		deleted_at = $18, edited = TRUE
+	WHERE id = $1 AND account_id = $19;`
+
+	return db.withAudit(ctx, a, func(tx pgx.Tx) (bool, error) {
+		st, err := tx.Exec(ctx, query,
+			t.ID, t.BI, t.BIRake, t.BIBounty, t.Players, t.TotalPrizePool, t.Started, t.Finished, durationSeconds(t.Duration),
+			t.MyPlace, t.MyPrize, t.Reentries, t.Name, t.Type, t.Free, t.Currency, t.Room, t.DeletedAt, t.AccountID,
+		)
+		if err != nil {
+			return false, fmt.Errorf("cannot update tournament item: %w", err)
+		}
+		return st.RowsAffected() == 1, nil
+	})
+}
+
+// withAudit runs change and, when it did something, saves the audit entry
+// in the same transaction.
+func (db *db) withAudit(ctx context.Context, a AuditEntry, change func(pgx.Tx) (bool, error)) (bool, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	ok, err := change(tx)
+	if err != nil || !ok {
+		return false, err
+	}
+	if a.Changes == nil {
+		a.Changes = []AuditChange{}
+	}
+	query := `INSERT INTO tournament_audit (tournament_id, account_id, action, changes, at) VALUES ($1, $2, $3, $4, $5);`
+	if _, err := tx.Exec(ctx, query, a.TournamentID, a.AccountID, a.Action, a.Changes, a.At); err != nil {
+		return false, fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return true, tx.Commit(ctx)
+}
+
+func durationSeconds(d *time.Duration) *int64 {
+	if d == nil {
+		return nil
+	}
+	seconds := int64(d.Seconds())
+	return &seconds
+}
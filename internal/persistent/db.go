@@ -2,10 +2,13 @@ package persistent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,9 +24,78 @@ type (
 
 		CreateTournamentsTable(ctx context.Context) error
 
-		FreeTournament(ctx context.Context, id string) (bool, error)
 		SaveTournaments(ctx context.Context, t Tournament) (bool, error)
+		SaveNewTournaments(ctx context.Context, ts []Tournament) (int, error)
 		ListTournaments(ctx context.Context, whereOpts ...WhereOpt) ([]Tournament, error)
+		IterateTournaments(ctx context.Context, fn func(Tournament) error, whereOpts ...WhereOpt) error
+		InsertTournament(ctx context.Context, t Tournament, a AuditEntry) (bool, error)
+		UpdateTournament(ctx context.Context, t Tournament, a AuditEntry) (bool, error)
+
+		CreateAuditTable(ctx context.Context) error
+		ListAudit(ctx context.Context, tournamentID string, accountID int64) ([]AuditEntry, error)
+
+		CreateTagsTables(ctx context.Context) error
+		AddTag(ctx context.Context, tournamentID string, accountID int64, tag string) (bool, error)
+		RemoveTag(ctx context.Context, tournamentID string, accountID int64, tag string) (bool, error)
+		ListTags(ctx context.Context, whereOpts ...WhereOpt) ([]TagUsage, error)
+		SaveNote(ctx context.Context, n Note) (int64, error)
+		UpdateNote(ctx context.Context, n Note) (bool, error)
+		DeleteNote(ctx context.Context, n Note) (bool, error)
+		ListNotes(ctx context.Context, tournamentID string, accountID int64) ([]Note, error)
+
+		CreatePlayersTables(ctx context.Context) error
+		SavePlayer(ctx context.Context, p Player) (int64, error)
+		UpdatePlayer(ctx context.Context, p Player) (bool, error)
+		ListPlayers(ctx context.Context) ([]Player, error)
+		SaveAccount(ctx context.Context, a Account) (int64, error)
+		UpdateAccount(ctx context.Context, a Account) (bool, error)
+		ListAccounts(ctx context.Context) ([]Account, error)
+		AdoptOrphans(ctx context.Context, a Account) error
+
+		CreateUsersTables(ctx context.Context) error
+		SaveUser(ctx context.Context, u User) (int64, error)
+		UpdateUser(ctx context.Context, u User) (bool, error)
+		DeleteUser(ctx context.Context, id int64) (bool, error)
+		ListUsers(ctx context.Context) ([]User, error)
+		SaveToken(ctx context.Context, t Token) (int64, error)
+		FindToken(ctx context.Context, hash string) (Token, bool, error)
+		TouchToken(ctx context.Context, id int64, at time.Time) error
+		ListTokens(ctx context.Context, userID int64) ([]Token, error)
+		DeleteToken(ctx context.Context, userID, id int64) (bool, error)
+		DeleteExpiredTokens(ctx context.Context, now time.Time) error
+
+		CreateTransactionsTable(ctx context.Context) error
+		SaveTransaction(ctx context.Context, t Transaction) (int64, error)
+		ListTransactions(ctx context.Context, whereOpts ...WhereOpt) ([]Transaction, error)
+		DeleteTransaction(ctx context.Context, id int64) (bool, error)
+
+		CreateRakebackRulesTable(ctx context.Context) error
+		SaveRakebackRule(ctx context.Context, r RakebackRule) (int64, error)
+		ListRakebackRules(ctx context.Context) ([]RakebackRule, error)
+		DeleteRakebackRule(ctx context.Context, id int64) (bool, error)
+
+		CreateStakingTable(ctx context.Context) error
+		SaveDeal(ctx context.Context, d Deal) (int64, error)
+		UpdateDeal(ctx context.Context, d Deal) (bool, error)
+		ListDeals(ctx context.Context) ([]Deal, error)
+		DeleteDeal(ctx context.Context, id int64) (bool, error)
+
+		CreateEventRulesTable(ctx context.Context) error
+		SaveEventRule(ctx context.Context, r EventRule) (int64, error)
+		ListEventRules(ctx context.Context) ([]EventRule, error)
+		DeleteEventRule(ctx context.Context, id int64) (bool, error)
+
+		CreatePayoutStructuresTable(ctx context.Context) error
+		SavePayoutStructure(ctx context.Context, s PayoutStructure) (int64, error)
+		UpdatePayoutStructure(ctx context.Context, s PayoutStructure) (bool, error)
+		ListPayoutStructures(ctx context.Context) ([]PayoutStructure, error)
+		DeletePayoutStructure(ctx context.Context, id int64) (bool, error)
+
+		CreateRatesTable(ctx context.Context) error
+		SaveRate(ctx context.Context, r Rate) error
+		ListRates(ctx context.Context) ([]Rate, error)
+
+		Restore(ctx context.Context, d Dump) error
 	}
 )
 
@@ -56,71 +128,144 @@ func (db *db) Stop() {
 }
 
 func (db *db) ListTournaments(ctx context.Context, whereOpts ...WhereOpt) ([]Tournament, error) {
-	query := `
-		SELECT * FROM tournaments
-	`
-	where := constructsOption(whereOpts...)
-	if where.ID != nil {
-		query += fmt.Sprintf(" WHERE id = '%s'", *where.ID)
-	}
-	rows, err := db.pool.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-
 	var tournamets []Tournament
-	for rows.Next() {
-		var t Tournament
-		if err := rows.Scan(&t.ID, &t.BI, &t.Players, &t.TotalPrizePool,
-			&t.Started, &t.MyPlace, &t.MyPrize, &t.Reentries, &t.Name, &t.Type, &t.Free); err != nil {
-			return nil, err
-		}
+	err := db.IterateTournaments(ctx, func(t Tournament) error {
 		tournamets = append(tournamets, t)
-	}
-	if err = rows.Err(); err != nil {
+		return nil
+	}, whereOpts...)
+	if err != nil {
 		return nil, err
 	}
 	return tournamets, nil
 }
 
-func (db *db) FreeTournament(ctx context.Context, id string) (bool, error) {
+// IterateTournaments streams tournaments ordered by start to fn without
+// loading them all. An error from fn stops the iteration and is returned.
+func (db *db) IterateTournaments(ctx context.Context, fn func(Tournament) error, whereOpts ...WhereOpt) error {
 	query := `
-	UPDATE tournaments SET free=$1
-		WHERE id = $2;
-	;`
-	st, err := db.pool.Exec(ctx, query, true, id)
+		SELECT id, bi, bi_rake, bi_bounty, players, total_prize_pool, started, finished, duration,
+			my_place, my_prize, reentries, name, type, free, currency, room, edited, deleted_at,
+			ARRAY(SELECT tag FROM tournament_tags tt
+				WHERE tt.tournament_id = tournaments.id AND tt.account_id = tournaments.account_id ORDER BY tag),
+			account_id
+		FROM tournaments
+	`
+	where, args := constructsOption(whereOpts...).sql()
+	query += where + " ORDER BY started"
+	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
-		return false, fmt.Errorf("cannot update tournament item: %w", err)
+		return err
 	}
-	return st.RowsAffected() == 1, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			t        Tournament
+			duration *int64
+		)
+		if err := rows.Scan(&t.ID, &t.BI, &t.BIRake, &t.BIBounty, &t.Players, &t.TotalPrizePool, &t.Started, &t.Finished, &duration,
+			&t.MyPlace, &t.MyPrize, &t.Reentries, &t.Name, &t.Type, &t.Free, &t.Currency, &t.Room, &t.Edited, &t.DeletedAt, &t.Tags, &t.AccountID); err != nil {
+			return err
+		}
+		if duration != nil {
+			d := time.Duration(*duration) * time.Second
+			t.Duration = &d
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
+// SaveTournaments inserts a new tournament. For a known one it only refreshes
+// the buy-in split and currency and fills in the end time and room when they
+// were missing, everything else is left as is. Tournaments edited by hand are
+// not touched at all. It reports whether the tournament was inserted.
 func (db *db) SaveTournaments(ctx context.Context, t Tournament) (bool, error) {
 
 	query := `
 	INSERT INTO tournaments (
-		id, bi, players, total_prize_pool, started, my_place, my_prize, reentries, name, type
+		id, bi, bi_rake, bi_bounty, players, total_prize_pool, started, finished, duration,
+		my_place, my_prize, reentries, name, type, currency, room, account_id
 	) VALUES (
-		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-	)ON CONFLICT (id) DO NOTHING;`
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+	) ON CONFLICT (id, account_id) DO UPDATE SET
+		finished = COALESCE(tournaments.finished, EXCLUDED.finished),
+		duration = COALESCE(tournaments.duration, EXCLUDED.duration),
+		bi_rake = EXCLUDED.bi_rake,
+		bi_bounty = EXCLUDED.bi_bounty,
+		currency = EXCLUDED.currency,
+		room = COALESCE(NULLIF(tournaments.room, ''), EXCLUDED.room)
+	WHERE (tournaments.finished, tournaments.bi_rake, tournaments.bi_bounty, tournaments.currency, tournaments.room)
+		IS DISTINCT FROM (COALESCE(tournaments.finished, EXCLUDED.finished), EXCLUDED.bi_rake, EXCLUDED.bi_bounty,
+		EXCLUDED.currency, COALESCE(NULLIF(tournaments.room, ''), EXCLUDED.room))
+		AND NOT tournaments.edited
+	RETURNING xmax = 0;`
 
-	st, err := db.pool.Exec(ctx, query,
+	var inserted bool
+	err := db.pool.QueryRow(ctx, query,
 		t.ID,
 		t.BI,
+		t.BIRake,
+		t.BIBounty,
 		t.Players,
 		t.TotalPrizePool,
 		t.Started,
+		t.Finished,
+		durationSeconds(t.Duration),
 		t.MyPlace,
 		t.MyPrize,
 		t.Reentries,
 		t.Name,
 		t.Type,
-	)
+		t.Currency,
+		t.Room,
+		t.AccountID,
+	).Scan(&inserted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to insert tournament: %w", err)
 	}
 
-	return st.RowsAffected() > 0, nil
+	return inserted, nil
+}
+
+// SaveNewTournaments inserts the tournaments in a single transaction, all of
+// them or none. Known ones are left untouched. It reports how many were
+// inserted.
+func (db *db) SaveNewTournaments(ctx context.Context, ts []Tournament) (int, error) {
+	query := `
+	INSERT INTO tournaments (
+		id, bi, bi_rake, bi_bounty, players, total_prize_pool, started, finished, duration,
+		my_place, my_prize, reentries, name, type, currency, room, account_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+	) ON CONFLICT (id, account_id) DO NOTHING;`
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	inserted := 0
+	for _, t := range ts {
+		st, err := tx.Exec(ctx, query,
+			t.ID, t.BI, t.BIRake, t.BIBounty, t.Players, t.TotalPrizePool, t.Started, t.Finished, durationSeconds(t.Duration),
+			t.MyPlace, t.MyPrize, t.Reentries, t.Name, t.Type, t.Currency, t.Room, t.AccountID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert tournament %s: %w", t.ID, err)
+		}
+		inserted += int(st.RowsAffected())
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return inserted, nil
 }
 
 func (db *db) CreateTournamentsTable(ctx context.Context) error {
@@ -143,5 +288,5 @@ func (db *db) CreateTournamentsTable(ctx context.Context) error {
 		return fmt.Errorf("failed to create tournaments table: %w", err)
 	}
 
-	return nil
+	return db.migrate(ctx, "tournaments", tournamentsMigrations)
 }
@@ -0,0 +1,81 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *db) CreateEventRulesTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS event_rules (
+		id BIGSERIAL PRIMARY KEY,
+		match TEXT NOT NULL,
+		by_name BOOLEAN NOT NULL DEFAULT FALSE,
+		key TEXT NOT NULL,
+		UNIQUE (match, by_name)
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create event_rules table: %w", err)
+	}
+	return nil
+}
+
+// SaveEventRule adds a rule or replaces the key of the rule with the same match.
+func (db *db) SaveEventRule(ctx context.Context, r EventRule) (int64, error) {
+	query := `
+	INSERT INTO event_rules (
+		match, by_name, key
+	) VALUES (
+		$1, $2, $3
+	) ON CONFLICT (match, by_name) DO UPDATE SET key = EXCLUDED.key
+	RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, r.Match, r.ByName, r.Key).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert event rule: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) ListEventRules(ctx context.Context) ([]EventRule, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, match, by_name, key FROM event_rules ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []EventRule
+	for rows.Next() {
+		var r EventRule
+		if err := rows.Scan(&r.ID, &r.Match, &r.ByName, &r.Key); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func (db *db) DeleteEventRule(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM event_rules WHERE id = $1;`, id)
+	if err != nil {
+		return false, fmt.Errorf("cannot delete event rule: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func restoreEventRule(ctx context.Context, tx pgx.Tx, r EventRule) error {
+	query := `
+	INSERT INTO event_rules (id, match, by_name, key) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (id) DO UPDATE SET
+		match = EXCLUDED.match,
+		by_name = EXCLUDED.by_name,
+		key = EXCLUDED.key;`
+
+	if _, err := tx.Exec(ctx, query, r.ID, r.Match, r.ByName, r.Key); err != nil {
+		return fmt.Errorf("failed to restore event rule %d: %w", r.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Columns added to existing tables after their first release. Every
+// statement must be safe to run again on an already migrated table.
+var tournamentsMigrations = []string{
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS finished TIMESTAMP`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS duration INT`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS bi_rake FLOAT4 NOT NULL DEFAULT 0`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS bi_bounty FLOAT4 NOT NULL DEFAULT 0`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD'`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS room TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS edited BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+	`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 0`,
+	widenPrimaryKey("tournaments", "id, account_id"),
+}
+
+var tagsMigrations = []string{
+	`ALTER TABLE tournament_tags ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 0`,
+	widenPrimaryKey("tournament_tags", "tournament_id, account_id, tag"),
+	`ALTER TABLE tournament_notes ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 0`,
+}
+
+var auditMigrations = []string{
+	`ALTER TABLE tournament_audit ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 0`,
+}
+
+var transactionsMigrations = []string{
+	`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS player_id BIGINT NOT NULL DEFAULT 0`,
+}
+
+// widenPrimaryKey replaces the primary key of a table with one on columns,
+// unless the key already has that many columns.
+func widenPrimaryKey(table, columns string) string {
+	return fmt.Sprintf(`DO $$ BEGIN
+		IF (SELECT array_length(conkey, 1) FROM pg_constraint
+			WHERE conrelid = '%[1]s'::regclass AND contype = 'p') < %[3]d THEN
+			ALTER TABLE %[1]s DROP CONSTRAINT %[1]s_pkey;
+			ALTER TABLE %[1]s ADD PRIMARY KEY (%[2]s);
+		END IF;
+	END $$`, table, columns, len(strings.Split(columns, ",")))
+}
+
+func (db *db) migrate(ctx context.Context, table string, statements []string) error {
+	for _, q := range statements {
+		if _, err := db.pool.Exec(ctx, q); err != nil {
+			return fmt.Errorf("failed to migrate %s table: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -6,14 +6,131 @@ type (
 	Tournament struct {
 		ID             string
 		BI             float32 //in $
+		BIRake         float32
+		BIBounty       float32
 		Players        int
 		TotalPrizePool float32 //without rake
 		Started        time.Time
+		Finished       *time.Time
+		Duration       *time.Duration
 		MyPlace        int
 		MyPrize        float32
 		Reentries      int
 		Name           string
 		Type           string
 		Free           bool
+		Currency       string
+		Room           string
+		Edited         bool
+		DeletedAt      *time.Time
+		Tags           []string
+		AccountID      int64
+	}
+	Player struct {
+		ID   int64
+		Name string
+	}
+	Account struct {
+		ID         int64
+		PlayerID   int64
+		Room       string
+		ScreenName string
+		SourceDir  string
+	}
+	User struct {
+		ID           int64
+		Name         string
+		PasswordHash string
+		Role         string
+		Players      []int64
+	}
+	Token struct {
+		ID       int64
+		UserID   int64
+		Name     string
+		Hash     string
+		Session  bool
+		Created  time.Time
+		LastUsed *time.Time
+		Expires  *time.Time
+	}
+	Note struct {
+		ID           int64
+		TournamentID string
+		AccountID    int64
+		Text         string
+		Created      time.Time
+		Updated      time.Time
+	}
+	TagUsage struct {
+		Tag         string
+		Tournaments int
+	}
+	Transaction struct {
+		ID       int64
+		Type     string
+		Amount   float32
+		Currency string
+		Date     time.Time
+		Room     string
+		Note     string
+		PlayerID int64
+	}
+	RakebackRule struct {
+		ID      int64
+		Name    string
+		Room    string
+		Percent float64
+		Tiers   []RakebackTier
+	}
+	Deal struct {
+		ID        int64
+		PlayerID  int64
+		Backer    string
+		Sold      float64
+		Markup    float64
+		PlayerCut float64
+		Makeup    string
+		Period    string
+		Start     time.Time
+		End       *time.Time
+		Note      string
+	}
+	EventRule struct {
+		ID     int64
+		Match  string
+		ByName bool
+		Key    string
+	}
+	PayoutStructure struct {
+		ID         int64
+		Name       string
+		Type       string
+		Event      string
+		MinPlayers int
+		MaxPlayers int
+		Places     []float64
+	}
+	Rate struct {
+		Date     time.Time
+		Currency string
+		USD      float64
+	}
+	AuditEntry struct {
+		ID           int64
+		TournamentID string
+		AccountID    int64
+		Action       string
+		Changes      []AuditChange
+		At           time.Time
+	}
+	AuditChange struct {
+		Field string `json:"field"`
+		Old   any    `json:"old"`
+		New   any    `json:"new"`
+	}
+	RakebackTier struct {
+		Threshold float64 `json:"threshold"`
+		Percent   float64 `json:"percent"`
 	}
 )
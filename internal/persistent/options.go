@@ -1,8 +1,27 @@
 package persistent
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type (
 	Where struct {
-		ID *string
+		ID          *string
+		IDs         []string
+		StartedFrom *time.Time
+		StartedTo   *time.Time
+		Types       []string
+		MinBI       *float32
+		MaxBI       *float32
+		// Tags keeps tournaments with any of them, NotTags drops those with any of them.
+		Tags     []string
+		NotTags  []string
+		Accounts []int64
+		Players  []int64
+		// Deleted includes soft deleted tournaments.
+		Deleted bool
 	}
 	WhereOpt func(where *Where)
 )
@@ -13,6 +32,82 @@ func WithID(id string) WhereOpt {
 	}
 }
 
+// WithIDs keeps the tournaments with one of the ids.
+func WithIDs(ids ...string) WhereOpt {
+	return func(w *Where) {
+		w.IDs = append(w.IDs, ids...)
+	}
+}
+
+// WithStartedFrom keeps tournaments started at or after from, and ledger
+// entries dated so.
+func WithStartedFrom(from time.Time) WhereOpt {
+	return func(w *Where) {
+		w.StartedFrom = &from
+	}
+}
+
+// WithStartedTo keeps tournaments started before to, and ledger entries
+// dated so.
+func WithStartedTo(to time.Time) WhereOpt {
+	return func(w *Where) {
+		w.StartedTo = &to
+	}
+}
+
+func WithTypes(types ...string) WhereOpt {
+	return func(w *Where) {
+		w.Types = append(w.Types, types...)
+	}
+}
+
+func WithMinBI(bi float32) WhereOpt {
+	return func(w *Where) {
+		w.MinBI = &bi
+	}
+}
+
+func WithMaxBI(bi float32) WhereOpt {
+	return func(w *Where) {
+		w.MaxBI = &bi
+	}
+}
+
+// WithTags keeps the tournaments marked with any of the tags.
+func WithTags(tags ...string) WhereOpt {
+	return func(w *Where) {
+		w.Tags = append(w.Tags, tags...)
+	}
+}
+
+// WithoutTags drops the tournaments marked with any of the tags.
+func WithoutTags(tags ...string) WhereOpt {
+	return func(w *Where) {
+		w.NotTags = append(w.NotTags, tags...)
+	}
+}
+
+func WithAccounts(ids ...int64) WhereOpt {
+	return func(w *Where) {
+		w.Accounts = append(w.Accounts, ids...)
+	}
+}
+
+// WithPlayers keeps the tournaments played on any account of the players,
+// and their ledger entries.
+func WithPlayers(ids ...int64) WhereOpt {
+	return func(w *Where) {
+		w.Players = append(w.Players, ids...)
+	}
+}
+
+// WithDeleted includes the tournaments deleted by hand.
+func WithDeleted() WhereOpt {
+	return func(w *Where) {
+		w.Deleted = true
+	}
+}
+
 func constructsOption(fns ...WhereOpt) Where {
 	o := Where{}
 	for _, f := range fns {
@@ -20,3 +115,83 @@ func constructsOption(fns ...WhereOpt) Where {
 	}
 	return o
 }
+
+// sql renders the where clause with positional arguments.
+func (w Where) sql() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if w.ID != nil {
+		add("id = $%d", *w.ID)
+	}
+	if w.IDs != nil {
+		add("id = ANY($%d)", w.IDs)
+	}
+	if w.StartedFrom != nil {
+		add("started >= $%d", *w.StartedFrom)
+	}
+	if w.StartedTo != nil {
+		add("started < $%d", *w.StartedTo)
+	}
+	if len(w.Types) > 0 {
+		add("type = ANY($%d)", w.Types)
+	}
+	if w.MinBI != nil {
+		add("bi >= $%d", *w.MinBI)
+	}
+	if w.MaxBI != nil {
+		add("bi <= $%d", *w.MaxBI)
+	}
+	if len(w.Tags) > 0 {
+		add(`EXISTS (SELECT 1 FROM tournament_tags tt WHERE tt.tournament_id = tournaments.id
+			AND tt.account_id = tournaments.account_id AND tt.tag = ANY($%d))`, w.Tags)
+	}
+	if len(w.NotTags) > 0 {
+		add(`NOT EXISTS (SELECT 1 FROM tournament_tags tt WHERE tt.tournament_id = tournaments.id
+			AND tt.account_id = tournaments.account_id AND tt.tag = ANY($%d))`, w.NotTags)
+	}
+	if len(w.Accounts) > 0 {
+		add("account_id = ANY($%d)", w.Accounts)
+	}
+	if len(w.Players) > 0 {
+		add("account_id IN (SELECT id FROM accounts WHERE player_id = ANY($%d))", w.Players)
+	}
+	if !w.Deleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// ledgerSQL renders the where clause of ledger entries: their date range and
+// players.
+func (w Where) ledgerSQL() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if w.StartedFrom != nil {
+		add("date >= $%d", *w.StartedFrom)
+	}
+	if w.StartedTo != nil {
+		add("date < $%d", *w.StartedTo)
+	}
+	if len(w.Players) > 0 {
+		add("player_id = ANY($%d)", w.Players)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,100 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *db) CreatePayoutStructuresTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS payout_structures (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL DEFAULT '',
+		event TEXT NOT NULL DEFAULT '',
+		min_players INT NOT NULL DEFAULT 0,
+		max_players INT NOT NULL DEFAULT 0,
+		places JSONB NOT NULL DEFAULT '[]'
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create payout_structures table: %w", err)
+	}
+	return nil
+}
+
+func (db *db) SavePayoutStructure(ctx context.Context, s PayoutStructure) (int64, error) {
+	query := `
+	INSERT INTO payout_structures (
+		name, type, event, min_players, max_players, places
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	) RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, s.Name, s.Type, s.Event, s.MinPlayers, s.MaxPlayers, s.Places).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert payout structure: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) UpdatePayoutStructure(ctx context.Context, s PayoutStructure) (bool, error) {
+	query := `
+	UPDATE payout_structures SET
+		name = $2, type = $3, event = $4, min_players = $5, max_players = $6, places = $7
+	WHERE id = $1;`
+
+	st, err := db.pool.Exec(ctx, query, s.ID, s.Name, s.Type, s.Event, s.MinPlayers, s.MaxPlayers, s.Places)
+	if err != nil {
+		return false, fmt.Errorf("failed to update payout structure: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) ListPayoutStructures(ctx context.Context) ([]PayoutStructure, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, name, type, event, min_players, max_players, places FROM payout_structures ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var structures []PayoutStructure
+	for rows.Next() {
+		var s PayoutStructure
+		if err := rows.Scan(&s.ID, &s.Name, &s.Type, &s.Event, &s.MinPlayers, &s.MaxPlayers, &s.Places); err != nil {
+			return nil, err
+		}
+		structures = append(structures, s)
+	}
+	return structures, rows.Err()
+}
+
+func (db *db) DeletePayoutStructure(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM payout_structures WHERE id = $1;`, id)
+	if err != nil {
+		return false, fmt.Errorf("cannot delete payout structure: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func restorePayoutStructure(ctx context.Context, tx pgx.Tx, s PayoutStructure) error {
+	query := `
+	INSERT INTO payout_structures (
+		id, name, type, event, min_players, max_players, places
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7
+	) ON CONFLICT (id) DO UPDATE SET
+		name = EXCLUDED.name,
+		type = EXCLUDED.type,
+		event = EXCLUDED.event,
+		min_players = EXCLUDED.min_players,
+		max_players = EXCLUDED.max_players,
+		places = EXCLUDED.places;`
+
+	if _, err := tx.Exec(ctx, query, s.ID, s.Name, s.Type, s.Event, s.MinPlayers, s.MaxPlayers, s.Places); err != nil {
+		return fmt.Errorf("failed to restore payout structure %d: %w", s.ID, err)
+	}
+	return nil
+}
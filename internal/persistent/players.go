@@ -0,0 +1,123 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+)
+
+func (db *db) CreatePlayersTables(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS players (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE IF NOT EXISTS accounts (
+		id BIGSERIAL PRIMARY KEY,
+		player_id BIGINT NOT NULL REFERENCES players (id),
+		room TEXT NOT NULL,
+		screen_name TEXT NOT NULL,
+		source_dir TEXT NOT NULL DEFAULT '',
+		UNIQUE (room, screen_name)
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create players tables: %w", err)
+	}
+	return nil
+}
+
+func (db *db) SavePlayer(ctx context.Context, p Player) (int64, error) {
+	var id int64
+	if err := db.pool.QueryRow(ctx, `INSERT INTO players (name) VALUES ($1) RETURNING id`, p.Name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert player: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) UpdatePlayer(ctx context.Context, p Player) (bool, error) {
+	st, err := db.pool.Exec(ctx, `UPDATE players SET name = $2 WHERE id = $1`, p.ID, p.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to update player: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) ListPlayers(ctx context.Context) ([]Player, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, name FROM players ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []Player
+	for rows.Next() {
+		var p Player
+		if err := rows.Scan(&p.ID, &p.Name); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+func (db *db) SaveAccount(ctx context.Context, a Account) (int64, error) {
+	query := `
+	INSERT INTO accounts (
+		player_id, room, screen_name, source_dir
+	) VALUES (
+		$1, $2, $3, $4
+	) RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, a.PlayerID, a.Room, a.ScreenName, a.SourceDir).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert account: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) UpdateAccount(ctx context.Context, a Account) (bool, error) {
+	query := `UPDATE accounts SET player_id = $2, room = $3, screen_name = $4, source_dir = $5 WHERE id = $1`
+	st, err := db.pool.Exec(ctx, query, a.ID, a.PlayerID, a.Room, a.ScreenName, a.SourceDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to update account: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) ListAccounts(ctx context.Context) ([]Account, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, player_id, room, screen_name, source_dir FROM accounts ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []Account
+	for rows.Next() {
+		var a Account
+		if err := rows.Scan(&a.ID, &a.PlayerID, &a.Room, &a.ScreenName, &a.SourceDir); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+// AdoptOrphans hands the data saved before accounts existed over to a,
+// the ledger goes to its player.
+func (db *db) AdoptOrphans(ctx context.Context, a Account) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range []string{"tournaments", "tournament_tags", "tournament_notes", "tournament_audit"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`UPDATE %s SET account_id = $1 WHERE account_id = 0`, table), a.ID); err != nil {
+			return fmt.Errorf("failed to assign %s to account %d: %w", table, a.ID, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, `UPDATE transactions SET player_id = $1 WHERE player_id = 0`, a.PlayerID); err != nil {
+		return fmt.Errorf("failed to assign transactions to player %d: %w", a.PlayerID, err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,69 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+)
+
+func (db *db) CreateRakebackRulesTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS rakeback_rules (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		room TEXT NOT NULL DEFAULT '',
+		percent FLOAT8 NOT NULL,
+		tiers JSONB NOT NULL DEFAULT '[]'
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create rakeback_rules table: %w", err)
+	}
+	return nil
+}
+
+func (db *db) SaveRakebackRule(ctx context.Context, r RakebackRule) (int64, error) {
+	query := `
+	INSERT INTO rakeback_rules (
+		name, room, percent, tiers
+	) VALUES (
+		$1, $2, $3, $4
+	) RETURNING id;`
+
+	if r.Tiers == nil {
+		r.Tiers = []RakebackTier{}
+	}
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, r.Name, r.Room, r.Percent, r.Tiers).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert rakeback rule: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) ListRakebackRules(ctx context.Context) ([]RakebackRule, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, name, room, percent, tiers FROM rakeback_rules ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []RakebackRule
+	for rows.Next() {
+		var r RakebackRule
+		if err := rows.Scan(&r.ID, &r.Name, &r.Room, &r.Percent, &r.Tiers); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (db *db) DeleteRakebackRule(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM rakeback_rules WHERE id = $1;`, id)
+	if err != nil {
+		return false, fmt.Errorf("cannot delete rakeback rule: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
@@ -0,0 +1,57 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+)
+
+func (db *db) CreateRatesTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS exchange_rates (
+		date DATE NOT NULL,
+		currency TEXT NOT NULL,
+		usd FLOAT8 NOT NULL,
+		PRIMARY KEY (date, currency)
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create exchange_rates table: %w", err)
+	}
+	return nil
+}
+
+// SaveRate inserts a rate or replaces the one known for the same day.
+func (db *db) SaveRate(ctx context.Context, r Rate) error {
+	query := `
+	INSERT INTO exchange_rates (
+		date, currency, usd
+	) VALUES (
+		$1, $2, $3
+	) ON CONFLICT (date, currency) DO UPDATE SET usd = EXCLUDED.usd;`
+
+	if _, err := db.pool.Exec(ctx, query, r.Date, r.Currency, r.USD); err != nil {
+		return fmt.Errorf("failed to save rate: %w", err)
+	}
+	return nil
+}
+
+func (db *db) ListRates(ctx context.Context) ([]Rate, error) {
+	rows, err := db.pool.Query(ctx, `SELECT date, currency, usd FROM exchange_rates ORDER BY date, currency`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []Rate
+	for rows.Next() {
+		var r Rate
+		if err := rows.Scan(&r.Date, &r.Currency, &r.USD); err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
@@ -0,0 +1,233 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Dump is the user data of the database, as written to backups.
+type Dump struct {
+	Players       []Player
+	Accounts      []Account
+	Tournaments   []Tournament
+	Transactions  []Transaction
+	RakebackRules []RakebackRule
+	Deals         []Deal
+	EventRules    []EventRule
+	Payouts       []PayoutStructure
+	Rates         []Rate
+	Audit         []AuditEntry
+	Notes         []Note
+}
+
+// Restore writes a dump in a single transaction. Rows are matched by their
+// keys and overwritten, so restoring the same dump twice changes nothing.
+// Rows missing from the dump are kept.
+func (db *db) Restore(ctx context.Context, d Dump) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, p := range d.Players {
+		query := `INSERT INTO players (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name;`
+		if _, err := tx.Exec(ctx, query, p.ID, p.Name); err != nil {
+			return fmt.Errorf("failed to restore player %d: %w", p.ID, err)
+		}
+	}
+	for _, a := range d.Accounts {
+		query := `
+		INSERT INTO accounts (id, player_id, room, screen_name, source_dir) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			player_id = EXCLUDED.player_id,
+			room = EXCLUDED.room,
+			screen_name = EXCLUDED.screen_name,
+			source_dir = EXCLUDED.source_dir;`
+		if _, err := tx.Exec(ctx, query, a.ID, a.PlayerID, a.Room, a.ScreenName, a.SourceDir); err != nil {
+			return fmt.Errorf("failed to restore account %d: %w", a.ID, err)
+		}
+	}
+	for _, t := range d.Tournaments {
+		if err := restoreTournament(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	for _, t := range d.Transactions {
+		if err := restoreTransaction(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	for _, r := range d.RakebackRules {
+		if err := restoreRakebackRule(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+	for _, deal := range d.Deals {
+		if err := restoreDeal(ctx, tx, deal); err != nil {
+			return err
+		}
+	}
+	for _, r := range d.EventRules {
+		if err := restoreEventRule(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+	for _, s := range d.Payouts {
+		if err := restorePayoutStructure(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+	for _, r := range d.Rates {
+		query := `
+		INSERT INTO exchange_rates (date, currency, usd) VALUES ($1, $2, $3)
+		ON CONFLICT (date, currency) DO UPDATE SET usd = EXCLUDED.usd;`
+		if _, err := tx.Exec(ctx, query, r.Date, r.Currency, r.USD); err != nil {
+			return fmt.Errorf("failed to restore rate: %w", err)
+		}
+	}
+
+	for _, n := range d.Notes {
+		query := `
+		INSERT INTO tournament_notes (id, tournament_id, account_id, text, created, updated) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			tournament_id = EXCLUDED.tournament_id,
+			account_id = EXCLUDED.account_id,
+			text = EXCLUDED.text,
+			created = EXCLUDED.created,
+			updated = EXCLUDED.updated;`
+		if _, err := tx.Exec(ctx, query, n.ID, n.TournamentID, n.AccountID, n.Text, n.Created, n.Updated); err != nil {
+			return fmt.Errorf("failed to restore note %d: %w", n.ID, err)
+		}
+	}
+	for _, a := range d.Audit {
+		if err := restoreAudit(ctx, tx, a); err != nil {
+			return err
+		}
+	}
+
+	// Ids were given explicitly, new rows must be numbered after them.
+	for _, table := range []string{"players", "accounts", "transactions", "rakeback_rules", "staking_deals", "event_rules", "payout_structures", "tournament_audit", "tournament_notes"} {
+		if err := resetSequence(ctx, tx, table); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func restoreTournament(ctx context.Context, tx pgx.Tx, t Tournament) error {
+	query := `
+	INSERT INTO tournaments (
+		id, bi, bi_rake, bi_bounty, players, total_prize_pool, started, finished, duration,
+		my_place, my_prize, reentries, name, type, free, currency, room, edited, deleted_at, account_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+	) ON CONFLICT (id, account_id) DO UPDATE SET
+		bi = EXCLUDED.bi,
+		bi_rake = EXCLUDED.bi_rake,
+		bi_bounty = EXCLUDED.bi_bounty,
+		players = EXCLUDED.players,
+		total_prize_pool = EXCLUDED.total_prize_pool,
+		started = EXCLUDED.started,
+		finished = EXCLUDED.finished,
+		duration = EXCLUDED.duration,
+		my_place = EXCLUDED.my_place,
+		my_prize = EXCLUDED.my_prize,
+		reentries = EXCLUDED.reentries,
+		name = EXCLUDED.name,
+		type = EXCLUDED.type,
+		free = EXCLUDED.free,
+		currency = EXCLUDED.currency,
+		room = EXCLUDED.room,
+		edited = EXCLUDED.edited,
+		deleted_at = EXCLUDED.deleted_at;`
+
+	_, err := tx.Exec(ctx, query,
+		t.ID, t.BI, t.BIRake, t.BIBounty, t.Players, t.TotalPrizePool, t.Started, t.Finished, durationSeconds(t.Duration),
+		t.MyPlace, t.MyPrize, t.Reentries, t.Name, t.Type, t.Free, t.Currency, t.Room, t.Edited, t.DeletedAt, t.AccountID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore tournament #%s: %w", t.ID, err)
+	}
+	for _, tag := range t.Tags {
+		if _, err := tx.Exec(ctx, `INSERT INTO tournament_tags (tournament_id, account_id, tag) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, t.ID, t.AccountID, tag); err != nil {
+			return fmt.Errorf("failed to restore tags of #%s: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+func restoreTransaction(ctx context.Context, tx pgx.Tx, t Transaction) error {
+	query := `
+	INSERT INTO transactions (
+		id, type, amount, currency, date, room, note, player_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8
+	) ON CONFLICT (id) DO UPDATE SET
+		type = EXCLUDED.type,
+		amount = EXCLUDED.amount,
+		currency = EXCLUDED.currency,
+		date = EXCLUDED.date,
+		room = EXCLUDED.room,
+		note = EXCLUDED.note,
+		player_id = EXCLUDED.player_id;`
+
+	_, err := tx.Exec(ctx, query, t.ID, t.Type, t.Amount, t.Currency, t.Date, t.Room, t.Note, t.PlayerID)
+	if err != nil {
+		return fmt.Errorf("failed to restore transaction %d: %w", t.ID, err)
+	}
+	return nil
+}
+
+func restoreRakebackRule(ctx context.Context, tx pgx.Tx, r RakebackRule) error {
+	query := `
+	INSERT INTO rakeback_rules (
+		id, name, room, percent, tiers
+	) VALUES (
+		$1, $2, $3, $4, $5
+	) ON CONFLICT (id) DO UPDATE SET
+		name = EXCLUDED.name,
+		room = EXCLUDED.room,
+		percent = EXCLUDED.percent,
+		tiers = EXCLUDED.tiers;`
+
+	if r.Tiers == nil {
+		r.Tiers = []RakebackTier{}
+	}
+	if _, err := tx.Exec(ctx, query, r.ID, r.Name, r.Room, r.Percent, r.Tiers); err != nil {
+		return fmt.Errorf("failed to restore rakeback rule %d: %w", r.ID, err)
+	}
+	return nil
+}
+
+func restoreAudit(ctx context.Context, tx pgx.Tx, a AuditEntry) error {
+	query := `
+	INSERT INTO tournament_audit (
+		id, tournament_id, account_id, action, changes, at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	) ON CONFLICT (id) DO UPDATE SET
+		tournament_id = EXCLUDED.tournament_id,
+		account_id = EXCLUDED.account_id,
+		action = EXCLUDED.action,
+		changes = EXCLUDED.changes,
+		at = EXCLUDED.at;`
+
+	if a.Changes == nil {
+		a.Changes = []AuditChange{}
+	}
+	if _, err := tx.Exec(ctx, query, a.ID, a.TournamentID, a.AccountID, a.Action, a.Changes, a.At); err != nil {
+		return fmt.Errorf("failed to restore audit entry %d: %w", a.ID, err)
+	}
+	return nil
+}
+
+func resetSequence(ctx context.Context, tx pgx.Tx, table string) error {
+	query := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %[1]s`, table)
+	if _, err := tx.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to reset %s ids: %w", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const dealColumns = `id, player_id, backer, sold, markup, player_cut, makeup, period, start, "end", note`
+
+func (db *db) CreateStakingTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS staking_deals (
+		id BIGSERIAL PRIMARY KEY,
+		player_id BIGINT NOT NULL REFERENCES players (id),
+		backer TEXT NOT NULL,
+		sold FLOAT8 NOT NULL,
+		markup FLOAT8 NOT NULL DEFAULT 1,
+		player_cut FLOAT8 NOT NULL DEFAULT 0,
+		makeup TEXT NOT NULL,
+		period TEXT NOT NULL,
+		start TIMESTAMP NOT NULL,
+		"end" TIMESTAMP,
+		note TEXT NOT NULL DEFAULT ''
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create staking_deals table: %w", err)
+	}
+	return nil
+}
+
+func (db *db) SaveDeal(ctx context.Context, d Deal) (int64, error) {
+	query := `
+	INSERT INTO staking_deals (
+		player_id, backer, sold, markup, player_cut, makeup, period, start, "end", note
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+	) RETURNING id;`
+
+	var id int64
+	err := db.pool.QueryRow(ctx, query,
+		d.PlayerID, d.Backer, d.Sold, d.Markup, d.PlayerCut, d.Makeup, d.Period, d.Start, d.End, d.Note,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert deal: %w", err)
+	}
+	return id, nil
+}
+
+func (db *db) UpdateDeal(ctx context.Context, d Deal) (bool, error) {
+	query := `
+	UPDATE staking_deals SET
+		player_id = $2, backer = $3, sold = $4, markup = $5, player_cut = $6,
+		makeup = $7, period = $8, start = $9, "end" = $10, note = $11
+	WHERE id = $1;`
+
+	st, err := db.pool.Exec(ctx, query,
+		d.ID, d.PlayerID, d.Backer, d.Sold, d.Markup, d.PlayerCut, d.Makeup, d.Period, d.Start, d.End, d.Note,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update deal: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) ListDeals(ctx context.Context) ([]Deal, error) {
+	rows, err := db.pool.Query(ctx, `SELECT `+dealColumns+` FROM staking_deals ORDER BY start, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deals []Deal
+	for rows.Next() {
+		var d Deal
+		err := rows.Scan(&d.ID, &d.PlayerID, &d.Backer, &d.Sold, &d.Markup, &d.PlayerCut, &d.Makeup, &d.Period, &d.Start, &d.End, &d.Note)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, d)
+	}
+	return deals, rows.Err()
+}
+
+func (db *db) DeleteDeal(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM staking_deals WHERE id = $1;`, id)
+	if err != nil {
+		return false, fmt.Errorf("cannot delete deal: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func restoreDeal(ctx context.Context, tx pgx.Tx, d Deal) error {
+	query := `
+	INSERT INTO staking_deals (` + dealColumns + `) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+	) ON CONFLICT (id) DO UPDATE SET
+		player_id = EXCLUDED.player_id,
+		backer = EXCLUDED.backer,
+		sold = EXCLUDED.sold,
+		markup = EXCLUDED.markup,
+		player_cut = EXCLUDED.player_cut,
+		makeup = EXCLUDED.makeup,
+		period = EXCLUDED.period,
+		start = EXCLUDED.start,
+		"end" = EXCLUDED."end",
+		note = EXCLUDED.note;`
+
+	_, err := tx.Exec(ctx, query,
+		d.ID, d.PlayerID, d.Backer, d.Sold, d.Markup, d.PlayerCut, d.Makeup, d.Period, d.Start, d.End, d.Note,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore deal %d: %w", d.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+)
+
+func (db *db) CreateTagsTables(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS tournament_tags (
+		tournament_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (tournament_id, tag)
+	);
+	CREATE INDEX IF NOT EXISTS tournament_tags_tag ON tournament_tags (tag);
+	CREATE TABLE IF NOT EXISTS tournament_notes (
+		id BIGSERIAL PRIMARY KEY,
+		tournament_id TEXT NOT NULL,
+		text TEXT NOT NULL,
+		created TIMESTAMP NOT NULL,
+		updated TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS tournament_notes_tournament_id ON tournament_notes (tournament_id);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create tags and notes tables: %w", err)
+	}
+	return db.migrate(ctx, "tournament_tags", tagsMigrations)
+}
+
+// AddTag reports false when the tournament already has the tag.
+func (db *db) AddTag(ctx context.Context, tournamentID string, accountID int64, tag string) (bool, error) {
+	query := `INSERT INTO tournament_tags (tournament_id, account_id, tag) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	st, err := db.pool.Exec(ctx, query, tournamentID, accountID, tag)
+	if err != nil {
+		return false, fmt.Errorf("failed to add tag: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) RemoveTag(ctx context.Context, tournamentID string, accountID int64, tag string) (bool, error) {
+	query := `DELETE FROM tournament_tags WHERE tournament_id = $1 AND account_id = $2 AND tag = $3`
+	st, err := db.pool.Exec(ctx, query, tournamentID, accountID, tag)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove tag: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+// ListTags counts the tagged tournaments of the options, deleted ones only
+// WithDeleted.
+func (db *db) ListTags(ctx context.Context, whereOpts ...WhereOpt) ([]TagUsage, error) {
+	where, args := constructsOption(whereOpts...).sql()
+	query := `
+		SELECT tt.tag, COUNT(*) FROM tournament_tags tt
+		WHERE (tt.tournament_id, tt.account_id) IN (SELECT id, account_id FROM tournaments` + where + `)
+		GROUP BY tt.tag ORDER BY tt.tag
+	`
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []TagUsage
+	for rows.Next() {
+		var t TagUsage
+		if err := rows.Scan(&t.Tag, &t.Tournaments); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (db *db) SaveNote(ctx context.Context, n Note) (int64, error) {
+	query := `
+	INSERT INTO tournament_notes (
+		tournament_id, account_id, text, created, updated
+	) VALUES (
+		$1, $2, $3, $4, $5
+	) RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, n.TournamentID, n.AccountID, n.Text, n.Created, n.Updated).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert note: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateNote changes the text of a note of the tournament.
+func (db *db) UpdateNote(ctx context.Context, n Note) (bool, error) {
+	query := `UPDATE tournament_notes SET text = $4, updated = $5 WHERE id = $1 AND tournament_id = $2 AND account_id = $3`
+	st, err := db.pool.Exec(ctx, query, n.ID, n.TournamentID, n.AccountID, n.Text, n.Updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to update note: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+// DeleteNote removes a note of the tournament, only its id and tournament are used.
+func (db *db) DeleteNote(ctx context.Context, n Note) (bool, error) {
+	query := `DELETE FROM tournament_notes WHERE id = $1 AND tournament_id = $2 AND account_id = $3`
+	st, err := db.pool.Exec(ctx, query, n.ID, n.TournamentID, n.AccountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete note: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+// ListNotes returns the notes of a tournament, of all of them when the id is empty.
+func (db *db) ListNotes(ctx context.Context, tournamentID string, accountID int64) ([]Note, error) {
+	query := `SELECT id, tournament_id, account_id, text, created, updated FROM tournament_notes`
+	var args []any
+	if tournamentID != "" {
+		query += ` WHERE tournament_id = $1 AND account_id = $2`
+		args = append(args, tournamentID, accountID)
+	}
+	rows, err := db.pool.Query(ctx, query+` ORDER BY created, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.ID, &n.TournamentID, &n.AccountID, &n.Text, &n.Created, &n.Updated); err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
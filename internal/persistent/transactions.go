@@ -0,0 +1,84 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+)
+
+func (db *db) CreateTransactionsTable(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS transactions (
+		id BIGSERIAL PRIMARY KEY,
+		type TEXT NOT NULL,
+		amount FLOAT4 NOT NULL,
+		currency TEXT NOT NULL,
+		date TIMESTAMP NOT NULL,
+		room TEXT NOT NULL DEFAULT '',
+		note TEXT NOT NULL DEFAULT ''
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create transactions table: %w", err)
+	}
+	return db.migrate(ctx, "transactions", transactionsMigrations)
+}
+
+func (db *db) SaveTransaction(ctx context.Context, t Transaction) (int64, error) {
+	query := `
+	INSERT INTO transactions (
+		type, amount, currency, date, room, note, player_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7
+	) RETURNING id;`
+
+	var id int64
+	err := db.pool.QueryRow(ctx, query,
+		t.Type,
+		t.Amount,
+		t.Currency,
+		t.Date,
+		t.Room,
+		t.Note,
+		t.PlayerID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert transaction: %w", err)
+	}
+	return id, nil
+}
+
+// ListTransactions returns the ledger entries in the date range and of the
+// players of the options, the others apply to tournaments only.
+func (db *db) ListTransactions(ctx context.Context, whereOpts ...WhereOpt) ([]Transaction, error) {
+	query := `
+		SELECT id, type, amount, currency, date, room, note, player_id FROM transactions
+	`
+	where, args := constructsOption(whereOpts...).ledgerSQL()
+	query += where + " ORDER BY date, id"
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []Transaction
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(&t.ID, &t.Type, &t.Amount, &t.Currency, &t.Date, &t.Room, &t.Note, &t.PlayerID); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (db *db) DeleteTransaction(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM transactions WHERE id = $1;`, id)
+	if err != nil {
+		return false, fmt.Errorf("cannot delete transaction: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
@@ -0,0 +1,170 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *db) CreateUsersTables(ctx context.Context) error {
+	q := `CREATE TABLE IF NOT EXISTS users (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		role TEXT NOT NULL,
+		players BIGINT[] NOT NULL DEFAULT '{}'
+	);
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id BIGSERIAL PRIMARY KEY,
+		user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+		name TEXT NOT NULL DEFAULT '',
+		hash TEXT NOT NULL UNIQUE,
+		session BOOLEAN NOT NULL DEFAULT FALSE,
+		created TIMESTAMP NOT NULL,
+		last_used TIMESTAMP,
+		expires TIMESTAMP
+	);`
+
+	_, err := db.pool.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to create users tables: %w", err)
+	}
+	return nil
+}
+
+func (db *db) SaveUser(ctx context.Context, u User) (int64, error) {
+	query := `
+	INSERT INTO users (
+		name, password_hash, role, players
+	) VALUES (
+		$1, $2, $3, $4
+	) RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, u.Name, u.PasswordHash, u.Role, playerIDs(u.Players)).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert user: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateUser changes the role and players of a user, and the password
+// unless the hash is empty.
+func (db *db) UpdateUser(ctx context.Context, u User) (bool, error) {
+	query := `
+	UPDATE users SET
+		name = $2, role = $3, players = $4,
+		password_hash = COALESCE(NULLIF($5, ''), password_hash)
+	WHERE id = $1;`
+	st, err := db.pool.Exec(ctx, query, u.ID, u.Name, u.Role, playerIDs(u.Players), u.PasswordHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to update user: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) DeleteUser(ctx context.Context, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := db.pool.Query(ctx, `SELECT id, name, password_hash, role, players FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.PasswordHash, &u.Role, &u.Players); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (db *db) SaveToken(ctx context.Context, t Token) (int64, error) {
+	query := `
+	INSERT INTO api_tokens (
+		user_id, name, hash, session, created, expires
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	) RETURNING id;`
+
+	var id int64
+	if err := db.pool.QueryRow(ctx, query, t.UserID, t.Name, t.Hash, t.Session, t.Created, t.Expires).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to insert token: %w", err)
+	}
+	return id, nil
+}
+
+// FindToken looks a token up by the hash of its secret.
+func (db *db) FindToken(ctx context.Context, hash string) (Token, bool, error) {
+	query := `SELECT id, user_id, name, hash, session, created, last_used, expires FROM api_tokens WHERE hash = $1`
+	var t Token
+	err := db.pool.QueryRow(ctx, query, hash).Scan(&t.ID, &t.UserID, &t.Name, &t.Hash, &t.Session, &t.Created, &t.LastUsed, &t.Expires)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Token{}, false, nil
+	}
+	if err != nil {
+		return Token{}, false, fmt.Errorf("failed to find token: %w", err)
+	}
+	return t, true, nil
+}
+
+func (db *db) TouchToken(ctx context.Context, id int64, at time.Time) error {
+	if _, err := db.pool.Exec(ctx, `UPDATE api_tokens SET last_used = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("failed to touch token: %w", err)
+	}
+	return nil
+}
+
+func (db *db) ListTokens(ctx context.Context, userID int64) ([]Token, error) {
+	query := `SELECT id, user_id, name, hash, session, created, last_used, expires FROM api_tokens WHERE user_id = $1 ORDER BY id`
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []Token
+	for rows.Next() {
+		var t Token
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Hash, &t.Session, &t.Created, &t.LastUsed, &t.Expires); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (db *db) DeleteToken(ctx context.Context, userID, id int64) (bool, error) {
+	st, err := db.pool.Exec(ctx, `DELETE FROM api_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete token: %w", err)
+	}
+	return st.RowsAffected() == 1, nil
+}
+
+func (db *db) DeleteExpiredTokens(ctx context.Context, now time.Time) error {
+	if _, err := db.pool.Exec(ctx, `DELETE FROM api_tokens WHERE expires <= $1`, now); err != nil {
+		return fmt.Errorf("failed to delete expired tokens: %w", err)
+	}
+	return nil
+}
+
+// playerIDs keeps NOT NULL happy for users without a players limit.
+func playerIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
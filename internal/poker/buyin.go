@@ -0,0 +1,35 @@
+package poker
+
+type (
+	// BuyIn is the split of the buy-in line, in $.
+	BuyIn struct {
+		Prize  float32
+		Rake   float32
+		Bounty float32
+		// Currency the tournament was played in.
+		Currency Currency
+	}
+)
+
+// NewBuyIn splits the components of a buy-in line: "prize+rake+bounty",
+// "prize+rake" or a single amount without known rake.
+func NewBuyIn(parts []float32) BuyIn {
+	var b BuyIn
+	switch len(parts) {
+	case 0:
+	case 1:
+		b.Prize = parts[0]
+	case 2:
+		b.Prize, b.Rake = parts[0], parts[1]
+	default:
+		b.Prize, b.Rake = parts[0], parts[1]
+		for _, p := range parts[2:] {
+			b.Bounty += p
+		}
+	}
+	return b
+}
+
+func (b BuyIn) Total() float32 {
+	return b.Prize + b.Rake + b.Bounty
+}
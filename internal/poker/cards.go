@@ -0,0 +1,148 @@
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+type (
+	// Card is one of the 52 cards, its rank times four plus its suit.
+	Card uint8
+	// CardSet holds cards as bits, the bit of a card is its value.
+	CardSet uint64
+)
+
+const (
+	// Ranks are counted from the deuce, the ace is the highest.
+	NumRanks = 13
+	NumSuits = 4
+	NumCards = NumRanks * NumSuits
+
+	rankChars = "23456789TJQKA"
+	suitChars = "cdhs"
+)
+
+func NewCard(rank, suit int) Card {
+	return Card(rank*NumSuits + suit)
+}
+
+// ParseCard reads a card written as its rank and suit, e.g. Ah or td.
+func ParseCard(s string) (Card, error) {
+	if len(s) != 2 {
+		return 0, fmt.Errorf("invalid card %q", s)
+	}
+	rank := strings.IndexByte(rankChars, upper(s[0]))
+	suit := strings.IndexByte(suitChars, lower(s[1]))
+	if rank < 0 || suit < 0 {
+		return 0, fmt.Errorf("invalid card %q", s)
+	}
+	return NewCard(rank, suit), nil
+}
+
+// ParseCards reads cards written one after another, spaces and commas
+// between them are allowed: "AhKd", "Ah Kd" or "[Ah, Kd]".
+func ParseCards(s string) ([]Card, error) {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" ,[]", r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("invalid cards %q", s)
+	}
+	cards := make([]Card, 0, len(s)/2)
+	var seen CardSet
+	for i := 0; i < len(s); i += 2 {
+		c, err := ParseCard(s[i : i+2])
+		if err != nil {
+			return nil, err
+		}
+		if seen.Has(c) {
+			return nil, fmt.Errorf("card %s is repeated", c)
+		}
+		seen = seen.Add(c)
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+// Rank is 0 for a deuce up to 12 for an ace.
+func (c Card) Rank() int {
+	return int(c) / NumSuits
+}
+
+func (c Card) Suit() int {
+	return int(c) % NumSuits
+}
+
+func (c Card) String() string {
+	if c >= NumCards {
+		return "??"
+	}
+	return string([]byte{rankChars[c.Rank()], suitChars[c.Suit()]})
+}
+
+// RankChar is how a rank is written: 2..9, T, J, Q, K or A.
+func RankChar(rank int) byte {
+	return rankChars[rank]
+}
+
+// ParseRank reads a rank written as 2..9, T, J, Q, K or A.
+func ParseRank(b byte) (int, bool) {
+	rank := strings.IndexByte(rankChars, upper(b))
+	return rank, rank >= 0
+}
+
+func NewCardSet(cards ...Card) CardSet {
+	var s CardSet
+	for _, c := range cards {
+		s = s.Add(c)
+	}
+	return s
+}
+
+func (s CardSet) Add(c Card) CardSet {
+	return s | 1<<c
+}
+
+func (s CardSet) Has(c Card) bool {
+	return s&(1<<c) != 0
+}
+
+func (s CardSet) Count() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Cards lists the cards of the set from the lowest one.
+func (s CardSet) Cards() []Card {
+	res := make([]Card, 0, s.Count())
+	for rest := uint64(s); rest != 0; rest &= rest - 1 {
+		res = append(res, Card(bits.TrailingZeros64(rest)))
+	}
+	return res
+}
+
+func (s CardSet) String() string {
+	cards := s.Cards()
+	parts := make([]string, len(cards))
+	for i, c := range cards {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func upper(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - 'a' + 'A'
+	}
+	return b
+}
+
+func lower(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b - 'A' + 'a'
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package poker
+
+import "fmt"
+
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	CNY Currency = "CNY"
+)
+
+func ParseCurrency(s string) (Currency, error) {
+	switch c := Currency(s); c {
+	case "":
+		return USD, nil
+	case USD, EUR, CNY:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown currency %q", s)
+}
+
+// currencyBySymbol maps the symbols used in summaries.
+func currencyBySymbol(symbol string) Currency {
+	switch symbol {
+	case "€":
+		return EUR
+	case "¥":
+		return CNY
+	}
+	return USD
+}
+
+// ToDollar converts an amount with the fixed rates used by the summary parser.
+func (c Currency) ToDollar(v float32) float32 {
+	switch c {
+	case EUR:
+		return euroToDollar(v)
+	case CNY:
+		return yuanToDollar(v)
+	}
+	return v
+}
+
+// FromDollar reverses ToDollar, giving back the amount in the original currency.
+func (c Currency) FromDollar(v float32) float32 {
+	if one := c.ToDollar(1); one != 0 {
+		return v / one
+	}
+	return v
+}
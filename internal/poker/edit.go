@@ -0,0 +1,142 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type (
+	// TournamentPatch holds the fields of a manual correction, nil ones are kept.
+	TournamentPatch struct {
+		Name           *string         `json:"name,omitempty"`
+		Type           *TournamentType `json:"type,omitempty"`
+		Room           *string         `json:"room,omitempty"`
+		Currency       *Currency       `json:"currency,omitempty"`
+		Started        *time.Time      `json:"started,omitempty"`
+		Finished       *time.Time      `json:"finished,omitempty"`
+		BI             *float32        `json:"bi,omitempty"`
+		BIRake         *float32        `json:"bi_rake,omitempty"`
+		BIBounty       *float32        `json:"bi_bounty,omitempty"`
+		Players        *int            `json:"players,omitempty"`
+		TotalPrizePool *float32        `json:"total_prize_pool,omitempty"`
+		MyPlace        *int            `json:"my_place,omitempty"`
+		MyPrize        *float32        `json:"my_prize,omitempty"`
+		Reentries      *int            `json:"reentries,omitempty"`
+		Free           *bool           `json:"free,omitempty"`
+	}
+
+	FieldChange struct {
+		Field string `json:"field"`
+		Old   any    `json:"old"`
+		New   any    `json:"new"`
+	}
+
+	AuditAction string
+
+	// AuditEntry records one manual change of a tournament.
+	AuditEntry struct {
+		ID           int64         `json:"id"`
+		TournamentID string        `json:"tournament_id"`
+		AccountID    int64         `json:"account_id"`
+		Action       AuditAction   `json:"action"`
+		Changes      []FieldChange `json:"changes"`
+		At           time.Time     `json:"at"`
+	}
+)
+
+const (
+	AuditCreate   AuditAction = "create"
+	AuditUpdate   AuditAction = "update"
+	AuditDelete   AuditAction = "delete"
+	AuditUndelete AuditAction = "undelete"
+)
+
+// Apply returns the patched tournament together with the fields that changed.
+func (p TournamentPatch) Apply(t Tournament) (Tournament, []FieldChange) {
+	var changes []FieldChange
+	set(&changes, "name", &t.Name, p.Name)
+	set(&changes, "type", &t.Type, p.Type)
+	set(&changes, "room", &t.Room, p.Room)
+	set(&changes, "currency", &t.Currency, p.Currency)
+	set(&changes, "bi", &t.BI, p.BI)
+	set(&changes, "bi_rake", &t.BIRake, p.BIRake)
+	set(&changes, "bi_bounty", &t.BIBounty, p.BIBounty)
+	set(&changes, "players", &t.Players, p.Players)
+	set(&changes, "total_prize_pool", &t.TotalPrizePool, p.TotalPrizePool)
+	set(&changes, "my_place", &t.MyPlace, p.MyPlace)
+	set(&changes, "my_prize", &t.MyPrize, p.MyPrize)
+	set(&changes, "reentries", &t.Reentries, p.Reentries)
+	set(&changes, "free", &t.Free, p.Free)
+
+	// The duration follows both ends of the tournament.
+	finished := t.Finished
+	if p.Started != nil && !p.Started.Equal(t.Started) {
+		changes = append(changes, FieldChange{Field: "started", Old: t.Started, New: *p.Started})
+		t.Started = *p.Started
+	}
+	if p.Finished != nil && !p.Finished.Equal(t.Finished) {
+		changes = append(changes, FieldChange{Field: "finished", Old: t.Finished, New: *p.Finished})
+		finished = *p.Finished
+	}
+	if !finished.IsZero() {
+		t.Finished, t.Duration = finished, finished.Sub(t.Started)
+	}
+	return t, changes
+}
+
+func set[T comparable](changes *[]FieldChange, field string, dst *T, v *T) {
+	if v == nil || *dst == *v {
+		return
+	}
+	*changes = append(*changes, FieldChange{Field: field, Old: *dst, New: *v})
+	*dst = *v
+}
+
+// Validate checks a tournament entered or corrected by hand.
+func (t Tournament) Validate() error {
+	if t.ID == "" {
+		return errors.New("tournament id is required")
+	}
+	if t.Started.IsZero() {
+		return errors.New("start time is required")
+	}
+	if !t.Finished.IsZero() && t.Finished.Before(t.Started) {
+		return errors.New("tournament finished before it started")
+	}
+	if !slices.Contains(TournamentTypes, t.Type) {
+		return fmt.Errorf("unknown tournament type %q", t.Type)
+	}
+	if _, err := ParseCurrency(string(t.Currency)); err != nil {
+		return err
+	}
+	if t.BI < 0 || t.BIRake < 0 || t.BIBounty < 0 || t.TotalPrizePool < 0 || t.MyPrize < 0 {
+		return errors.New("amounts cannot be negative")
+	}
+	if t.BIRake+t.BIBounty > t.BI {
+		return errors.New("rake and bounty are parts of the buy-in")
+	}
+	if t.Players < 0 || t.MyPlace < 0 || t.Reentries < 0 {
+		return errors.New("players, place and re-entries cannot be negative")
+	}
+	if t.Players > 0 && t.MyPlace > t.Players {
+		return fmt.Errorf("place %d is beyond the %d players", t.MyPlace, t.Players)
+	}
+	return nil
+}
+
+// PatchOf sets every field of t, applying it to an empty tournament lists
+// all of its values.
+func PatchOf(t Tournament) TournamentPatch {
+	p := TournamentPatch{
+		Name: &t.Name, Type: &t.Type, Room: &t.Room, Currency: &t.Currency, Started: &t.Started,
+		BI: &t.BI, BIRake: &t.BIRake, BIBounty: &t.BIBounty, Players: &t.Players,
+		TotalPrizePool: &t.TotalPrizePool, MyPlace: &t.MyPlace, MyPrize: &t.MyPrize,
+		Reentries: &t.Reentries, Free: &t.Free,
+	}
+	if !t.Finished.IsZero() {
+		p.Finished = &t.Finished
+	}
+	return p
+}
@@ -0,0 +1,412 @@
+// Package equity computes the all-in equity of Hold'em hands and ranges:
+// the share of the pot every player wins on average once the board is
+// dealt, ties split.
+//
+// The deals are enumerated exactly when there are few enough of them and
+// sampled otherwise. Either way the work is spread over the cores.
+package equity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/poker/eval"
+)
+
+const (
+	MaxPlayers = 10
+	// ExactLimit bounds the deals enumerated, beyond it they are sampled.
+	ExactLimit = 20_000_000
+	// DefaultIterations are the deals sampled when no count is asked for.
+	DefaultIterations = 500_000
+	MaxIterations     = 20_000_000
+
+	boardSize = 5
+	// batchSize is how many work items a worker takes at once.
+	batchSize = 16
+	// maxRedeals bounds the tries to deal combos that do not collide.
+	maxRedeals = 10_000
+)
+
+type (
+	Options struct {
+		Board []poker.Card
+		// Dead cards are out of the deck, e.g. folded or burnt.
+		Dead []poker.Card
+		// Iterations sample that many deals, zero enumerates them all when
+		// feasible.
+		Iterations int
+		// Seed makes the sampling repeatable, each worker draws from its own
+		// source seeded from it.
+		Seed int64
+		// Workers run in parallel, all the cores by default.
+		Workers int
+	}
+
+	// Player is the outcome of a player in percents: Win and Tie are the
+	// chances to win alone and to split, Equity is the expected share of
+	// the pot.
+	Player struct {
+		Equity float64 `json:"equity"`
+		Win    float64 `json:"win"`
+		Tie    float64 `json:"tie"`
+	}
+
+	Result struct {
+		Players []Player `json:"players"`
+		// Exact is false when the deals were sampled.
+		Exact bool  `json:"exact"`
+		Deals int64 `json:"deals"`
+	}
+)
+
+type (
+	combo struct {
+		cards  poker.CardSet
+		weight float64
+	}
+
+	game struct {
+		players [][]combo
+		board   poker.CardSet
+		// deck are the cards left to deal the board from.
+		deck    []poker.Card
+		missing int
+	}
+
+	tally struct {
+		equity, win, tie []float64
+		total            float64
+		deals            int64
+	}
+)
+
+// Calculate finds the equity of every range against the others.
+func Calculate(ctx context.Context, ranges []poker.Range, o Options) (Result, error) {
+	g, err := newGame(ranges, o)
+	if err != nil {
+		return Result{}, err
+	}
+	if o.Iterations < 0 || o.Iterations > MaxIterations {
+		return Result{}, fmt.Errorf("iterations must be within 0..%d", MaxIterations)
+	}
+	workers := o.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	exact := o.Iterations == 0 && g.deals() <= ExactLimit
+	var t *tally
+	if exact {
+		t, err = g.enumerate(ctx, workers)
+	} else {
+		iterations := o.Iterations
+		if iterations == 0 {
+			iterations = DefaultIterations
+		}
+		t, err = g.sample(ctx, workers, iterations, o.Seed)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if t.total == 0 {
+		return Result{}, errors.New("the hands cannot be dealt together")
+	}
+
+	res := Result{Players: make([]Player, len(ranges)), Exact: exact, Deals: t.deals}
+	for i := range res.Players {
+		res.Players[i] = Player{
+			Equity: 100 * t.equity[i] / t.total,
+			Win:    100 * t.win[i] / t.total,
+			Tie:    100 * t.tie[i] / t.total,
+		}
+	}
+	return res, nil
+}
+
+func newGame(ranges []poker.Range, o Options) (*game, error) {
+	if len(ranges) < 2 || len(ranges) > MaxPlayers {
+		return nil, fmt.Errorf("need 2 to %d players, got %d", MaxPlayers, len(ranges))
+	}
+	switch len(o.Board) {
+	case 0, 3, 4, 5:
+	default:
+		return nil, fmt.Errorf("board must have 0, 3, 4 or 5 cards, got %d", len(o.Board))
+	}
+	var known poker.CardSet
+	for _, c := range append(append([]poker.Card{}, o.Board...), o.Dead...) {
+		if c >= poker.NumCards {
+			return nil, fmt.Errorf("invalid card %d", c)
+		}
+		if known.Has(c) {
+			return nil, fmt.Errorf("card %s is repeated", c)
+		}
+		known = known.Add(c)
+	}
+
+	g := &game{board: poker.NewCardSet(o.Board...), missing: boardSize - len(o.Board)}
+	for c := poker.Card(0); c < poker.NumCards; c++ {
+		if !known.Has(c) {
+			g.deck = append(g.deck, c)
+		}
+	}
+	if len(g.deck)-2*len(ranges) < g.missing {
+		return nil, fmt.Errorf("%d cards are left, too few to deal %d hands and finish the board", len(g.deck), len(ranges))
+	}
+	for i, r := range ranges {
+		r = r.Without(known)
+		var combos []combo
+		for _, h := range r.Combos() {
+			combos = append(combos, combo{cards: h.Set(), weight: min(r[h], 1)})
+		}
+		if len(combos) == 0 {
+			return nil, fmt.Errorf("player %d has no hand left after the board and dead cards", i+1)
+		}
+		g.players = append(g.players, combos)
+	}
+	return g, nil
+}
+
+// deals bounds the number of deals to enumerate, the deals where the
+// cards collide included.
+func (g *game) deals() float64 {
+	n := 1.0
+	for _, combos := range g.players {
+		n *= float64(len(combos))
+	}
+	for i := 0; i < g.missing; i++ {
+		n = n * float64(len(g.deck)-i) / float64(i+1)
+	}
+	return n
+}
+
+func newTally(players int) *tally {
+	return &tally{
+		equity: make([]float64, players),
+		win:    make([]float64, players),
+		tie:    make([]float64, players),
+	}
+}
+
+func (t *tally) add(o *tally) {
+	for i := range t.equity {
+		t.equity[i] += o.equity[i]
+		t.win[i] += o.win[i]
+		t.tie[i] += o.tie[i]
+	}
+	t.total += o.total
+	t.deals += o.deals
+}
+
+// showdown splits the pot of a deal of the given weight between the best
+// hands.
+func (t *tally) showdown(hands []poker.CardSet, board poker.CardSet, weight float64) {
+	var values [MaxPlayers]eval.Value
+	var best eval.Value
+	winners := 0
+	for i, h := range hands {
+		values[i] = eval.EvalSet(h | board)
+		switch {
+		case values[i] > best:
+			best, winners = values[i], 1
+		case values[i] == best:
+			winners++
+		}
+	}
+	share := weight / float64(winners)
+	for i := range hands {
+		if values[i] != best {
+			continue
+		}
+		t.equity[i] += share
+		if winners == 1 {
+			t.win[i] += weight
+		} else {
+			t.tie[i] += weight
+		}
+	}
+	t.total += weight
+	t.deals++
+}
+
+// enumerate deals every combination of the combos with every board. A work
+// item is a combination of combos and, when cards are missing, the lowest
+// card of the deck dealt to the board, so even a single matchup is shared
+// between the workers.
+func (g *game) enumerate(ctx context.Context, workers int) (*tally, error) {
+	matchups := 1
+	for _, combos := range g.players {
+		matchups *= len(combos)
+	}
+	firsts := 1
+	if g.missing > 0 {
+		firsts = len(g.deck)
+	}
+	items := int64(matchups) * int64(firsts)
+
+	var next atomic.Int64
+	res := make([]*tally, workers)
+	var wg sync.WaitGroup
+	for w := range res {
+		res[w] = newTally(len(g.players))
+		wg.Add(1)
+		go func(t *tally) {
+			defer wg.Done()
+			hands := make([]poker.CardSet, len(g.players))
+			for ctx.Err() == nil {
+				start := next.Add(batchSize) - batchSize
+				if start >= items {
+					return
+				}
+				for item := start; item < min(start+batchSize, items); item++ {
+					g.enumerateItem(t, hands, int(item/int64(firsts)), int(item%int64(firsts)))
+				}
+			}
+		}(res[w])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	total := newTally(len(g.players))
+	for _, t := range res {
+		total.add(t)
+	}
+	return total, nil
+}
+
+func (g *game) enumerateItem(t *tally, hands []poker.CardSet, matchup, first int) {
+	var used poker.CardSet
+	weight := 1.0
+	for i, combos := range g.players {
+		c := combos[matchup%len(combos)]
+		matchup /= len(combos)
+		if used&c.cards != 0 {
+			return
+		}
+		used |= c.cards
+		hands[i] = c.cards
+		weight *= c.weight
+	}
+	if g.missing == 0 {
+		t.showdown(hands, g.board, weight)
+		return
+	}
+	if used.Has(g.deck[first]) {
+		return
+	}
+	g.enumerateBoards(t, hands, used, g.board.Add(g.deck[first]), first+1, g.missing-1, weight)
+}
+
+// enumerateBoards deals the left cards from the deck from position from on.
+func (g *game) enumerateBoards(t *tally, hands []poker.CardSet, used, board poker.CardSet, from, left int, weight float64) {
+	if left == 0 {
+		t.showdown(hands, board, weight)
+		return
+	}
+	for i := from; i <= len(g.deck)-left; i++ {
+		if c := g.deck[i]; !used.Has(c) {
+			g.enumerateBoards(t, hands, used, board.Add(c), i+1, left-1, weight)
+		}
+	}
+}
+
+// sample deals random combos, drawn by their weights, and random boards.
+func (g *game) sample(ctx context.Context, workers, iterations int, seed int64) (*tally, error) {
+	// cumulative[i] are the running sums of the weights of the combos of
+	// player i, to draw them by weight.
+	cumulative := make([][]float64, len(g.players))
+	for i, combos := range g.players {
+		var sum float64
+		for _, c := range combos {
+			sum += c.weight
+			cumulative[i] = append(cumulative[i], sum)
+		}
+		if sum == 0 {
+			return nil, fmt.Errorf("player %d has no weighted hand", i+1)
+		}
+	}
+
+	res := make([]*tally, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := range res {
+		res[w] = newTally(len(g.players))
+		n := iterations / workers
+		if w < iterations%workers {
+			n++
+		}
+		wg.Add(1)
+		go func(w, n int) {
+			defer wg.Done()
+			errs[w] = g.sampleDeals(ctx, res[w], cumulative, n, rand.New(rand.NewSource(seed+int64(w))))
+		}(w, n)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	total := newTally(len(g.players))
+	for w, t := range res {
+		if errs[w] != nil {
+			return nil, errs[w]
+		}
+		total.add(t)
+	}
+	return total, nil
+}
+
+func (g *game) sampleDeals(ctx context.Context, t *tally, cumulative [][]float64, n int, rnd *rand.Rand) error {
+	hands := make([]poker.CardSet, len(g.players))
+	for k := 0; k < n; k++ {
+		if k%1024 == 0 && ctx.Err() != nil {
+			return nil
+		}
+		used, ok := g.dealCombos(hands, cumulative, rnd)
+		if !ok {
+			return errors.New("the hands cannot be dealt together")
+		}
+		board := g.board
+		for left := g.missing; left > 0; {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c := g.deck[rnd.Intn(len(g.deck))]
+			if !used.Has(c) {
+				used = used.Add(c)
+				board = board.Add(c)
+				left--
+			}
+		}
+		t.showdown(hands, board, 1)
+	}
+	return nil
+}
+
+// dealCombos draws a combo for every player until none collide, which
+// keeps the chance of a deal proportional to the product of its weights.
+func (g *game) dealCombos(hands []poker.CardSet, cumulative [][]float64, rnd *rand.Rand) (poker.CardSet, bool) {
+redeal:
+	for try := 0; try < maxRedeals; try++ {
+		var used poker.CardSet
+		for i, combos := range g.players {
+			sums := cumulative[i]
+			j := sort.SearchFloat64s(sums, rnd.Float64()*sums[len(sums)-1])
+			c := combos[min(j, len(combos)-1)].cards
+			if used&c != 0 {
+				continue redeal
+			}
+			used |= c
+			hands[i] = c
+		}
+		return used, true
+	}
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package equity
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func ranges(t *testing.T, notations ...string) []poker.Range {
+	t.Helper()
+	res := make([]poker.Range, len(notations))
+	for i, s := range notations {
+		r, err := poker.ParseRange(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res[i] = r
+	}
+	return res
+}
+
+func cards(t *testing.T, s string) []poker.Card {
+	t.Helper()
+	res, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestCalculateExact(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []string
+		board   string
+		want    []float64
+		tie     float64
+	}{
+		{"aces against kings", []string{"AhAd", "KsKc"}, "", []float64{81.26, 18.74}, 0.38},
+		{"coin flip", []string{"AhKh", "2c2d"}, "", []float64{50.08, 49.92}, 0.63},
+		{"three way", []string{"AhAd", "KsKc", "QhQd"}, "", []float64{66.51, 18.88, 14.62}, 0.40},
+		{"set on the flop", []string{"AsKs", "7c7h"}, "Ah7d2c", []float64{1.62, 98.38}, 0},
+		{"range on the flop", []string{"AsKs", "7c7h, 2h2d"}, "Ah7d2c", []float64{1.77, 98.23}, 0},
+		{"river", []string{"AsKs", "7c7h"}, "Ah7d2c5s9s", []float64{0, 100}, 0},
+		{"split pot", []string{"AhKd", "AsKc"}, "2c3d4h8s9s", []float64{50, 50}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Calculate(context.Background(), ranges(t, tt.players...), Options{Board: cards(t, tt.board)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Exact {
+				t.Error("deals were sampled")
+			}
+			for i, want := range tt.want {
+				if got := res.Players[i].Equity; math.Abs(got-want) > 0.01 {
+					t.Errorf("equity of player %d = %.3f, want %.2f", i+1, got, want)
+				}
+				if got := res.Players[i].Tie; math.Abs(got-tt.tie) > 0.01 {
+					t.Errorf("tie of player %d = %.3f, want %.2f", i+1, got, tt.tie)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateSampled(t *testing.T) {
+	res, err := Calculate(context.Background(), ranges(t, "AhAd", "KsKc"), Options{Iterations: 200000, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Exact || res.Deals != 200000 {
+		t.Errorf("exact = %v, deals = %d, want 200000 sampled deals", res.Exact, res.Deals)
+	}
+	if got := res.Players[0].Equity; math.Abs(got-81.26) > 0.5 {
+		t.Errorf("equity = %.2f, want about 81.26", got)
+	}
+}
+
+func TestCalculateWeights(t *testing.T) {
+	ctx := context.Background()
+	flop := Options{Board: cards(t, "Jh7d2c")}
+	kings, err := Calculate(ctx, ranges(t, "AhAd", "KsKc"), flop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sevens, err := Calculate(ctx, ranges(t, "AhAd", "7s7c"), flop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	both, err := Calculate(ctx, ranges(t, "AhAd", "KsKc, 7s7c:0.5"), flop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := (kings.Players[0].Equity + 0.5*sevens.Players[0].Equity) / 1.5
+	if got := both.Players[0].Equity; math.Abs(got-want) > 1e-9 {
+		t.Errorf("weighted equity = %f, want %f", got, want)
+	}
+}
+
+func TestCalculateRejects(t *testing.T) {
+	deadAll := "2c2d2h2s3c3d3h3s4c4d4h4s5c5d5h5s6c6d6h6s7c7d7h7s8c8d8h8s9c9d9h9sTcTdThTsJcJdJhJsQcQdQhQs"
+	tests := []struct {
+		name    string
+		players []string
+		o       Options
+	}{
+		{"one player", []string{"AhAd"}, Options{}},
+		{"board of one card", []string{"AhAd", "KsKc"}, Options{Board: cards(t, "2c")}},
+		{"card on the board and dead", []string{"AhAd", "KsKc"}, Options{Board: cards(t, "2c3c4c"), Dead: cards(t, "2c")}},
+		{"hand on the board", []string{"AhAd", "KsKc"}, Options{Board: cards(t, "Ah3c4c")}},
+		{"hands collide", []string{"AhKd", "AhQs"}, Options{}},
+		{"hands collide sampled", []string{"AhKd", "AhQs"}, Options{Iterations: 10}},
+		{"deck too small", []string{"AhAd", "KsKc"}, Options{Dead: cards(t, deadAll), Iterations: 10}},
+		{"too many iterations", []string{"AhAd", "KsKc"}, Options{Iterations: MaxIterations + 1}},
+	}
+	for _, tt := range tests {
+		if _, err := Calculate(context.Background(), ranges(t, tt.players...), tt.o); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestCalculateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Calculate(ctx, ranges(t, "AhAd", "KsKc"), Options{}); err == nil {
+		t.Error("cancelled calculation returned no error")
+	}
+	if _, err := Calculate(ctx, ranges(t, "AhAd", "KsKc"), Options{Iterations: 1000}); err == nil {
+		t.Error("cancelled sampling returned no error")
+	}
+}
@@ -0,0 +1,181 @@
+// Package eval ranks Hold'em hands of five to seven cards by their best
+// five card hand.
+//
+// Flushes are looked up by the ranks of the flush suit, a 13 bit mask.
+// Other hands depend only on how many cards of every rank there are: the
+// counts are hashed without collisions into their position among all the
+// count arrays of the same size, and the value is read from a table built
+// once at start.
+package eval
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// Category is the kind of a hand, the higher the better.
+type Category uint8
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var categoryNames = [...]string{
+	HighCard:      "High Card",
+	OnePair:       "One Pair",
+	TwoPair:       "Two Pair",
+	ThreeOfAKind:  "Three of a Kind",
+	Straight:      "Straight",
+	Flush:         "Flush",
+	FullHouse:     "Full House",
+	FourOfAKind:   "Four of a Kind",
+	StraightFlush: "Straight Flush",
+}
+
+func (c Category) String() string {
+	if int(c) < len(categoryNames) {
+		return categoryNames[c]
+	}
+	return fmt.Sprintf("Category(%d)", c)
+}
+
+// Value is the strength of a hand, a stronger hand has a greater value and
+// equal hands have equal values. It holds the category and the ranks that
+// decide between hands of the category, from the most significant one.
+type Value uint32
+
+const (
+	categoryShift = 20
+	rankBits      = 4
+	maxKickers    = 5
+)
+
+func newValue(c Category, ranks ...int) Value {
+	v := Value(c) << categoryShift
+	for i, r := range ranks {
+		v |= Value(r) << (rankBits * (maxKickers - 1 - i))
+	}
+	return v
+}
+
+func (v Value) Category() Category {
+	return Category(v >> categoryShift)
+}
+
+// Ranks are the ranks deciding between hands of the category: the rank of
+// the quads, trips or pairs first, then the kickers.
+func (v Value) Ranks() []int {
+	n := [...]int{
+		HighCard: 5, OnePair: 4, TwoPair: 3, ThreeOfAKind: 3, Straight: 1,
+		Flush: 5, FullHouse: 2, FourOfAKind: 2, StraightFlush: 1,
+	}[v.Category()]
+	res := make([]int, n)
+	for i := range res {
+		res[i] = int(v>>(rankBits*(maxKickers-1-i))) & (1<<rankBits - 1)
+	}
+	return res
+}
+
+// String describes the hand, e.g. "Full House, Kings full of Fives".
+func (v Value) String() string {
+	r := v.Ranks()
+	switch v.Category() {
+	case OnePair:
+		return fmt.Sprintf("%s, %s", v.Category(), plural(r[0]))
+	case TwoPair:
+		return fmt.Sprintf("%s, %s and %s", v.Category(), plural(r[0]), plural(r[1]))
+	case ThreeOfAKind, FourOfAKind:
+		return fmt.Sprintf("%s, %s", v.Category(), plural(r[0]))
+	case FullHouse:
+		return fmt.Sprintf("%s, %s full of %s", v.Category(), plural(r[0]), plural(r[1]))
+	case Straight, StraightFlush:
+		return fmt.Sprintf("%s, %s high", v.Category(), rankNames[r[0]])
+	}
+	return fmt.Sprintf("%s, %s high", v.Category(), rankNames[r[0]])
+}
+
+var rankNames = [poker.NumRanks]string{"Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King", "Ace"}
+
+func plural(rank int) string {
+	if rank == 4 {
+		return "Sixes"
+	}
+	return rankNames[rank] + "s"
+}
+
+// Compare returns -1, 0 or +1 as the hand of a is weaker than, as strong
+// as or stronger than that of b.
+func Compare(a, b Value) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Evaluate ranks five to seven distinct cards.
+func Evaluate(cards []poker.Card) (Value, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, fmt.Errorf("need 5 to 7 cards, got %d", len(cards))
+	}
+	var seen poker.CardSet
+	for _, c := range cards {
+		if c >= poker.NumCards {
+			return 0, fmt.Errorf("invalid card %d", c)
+		}
+		if seen.Has(c) {
+			return 0, fmt.Errorf("card %s is repeated", c)
+		}
+		seen = seen.Add(c)
+	}
+	return Eval(cards...), nil
+}
+
+// Eval ranks five to seven distinct cards without checking them, it is
+// the one to call in loops.
+func Eval(cards ...poker.Card) Value {
+	var suits [poker.NumSuits]uint16
+	var counts [poker.NumRanks]uint8
+	for _, c := range cards {
+		suits[c.Suit()] |= 1 << c.Rank()
+		counts[c.Rank()]++
+	}
+	return evalRanks(&suits, &counts, len(cards))
+}
+
+// EvalSet ranks the five to seven cards of a set.
+func EvalSet(s poker.CardSet) Value {
+	var suits [poker.NumSuits]uint16
+	var counts [poker.NumRanks]uint8
+	n := 0
+	for rest := uint64(s); rest != 0; rest &= rest - 1 {
+		c := poker.Card(bits.TrailingZeros64(rest))
+		suits[c.Suit()] |= 1 << c.Rank()
+		counts[c.Rank()]++
+		n++
+	}
+	return evalRanks(&suits, &counts, n)
+}
+
+func evalRanks(suits *[poker.NumSuits]uint16, counts *[poker.NumRanks]uint8, n int) Value {
+	// With seven cards or less a flush rules out quads and full houses,
+	// so it is the best hand when there is one.
+	for _, mask := range suits {
+		if bits.OnesCount16(mask) >= 5 {
+			return flushes[mask]
+		}
+	}
+	return others[n][hashCounts(counts, n)]
+}
@@ -0,0 +1,223 @@
+package eval
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func TestEvalFiveCardsExhaustive(t *testing.T) {
+	want := [...]int{
+		HighCard:      1302540,
+		OnePair:       1098240,
+		TwoPair:       123552,
+		ThreeOfAKind:  54912,
+		Straight:      10200,
+		Flush:         5108,
+		FullHouse:     3744,
+		FourOfAKind:   624,
+		StraightFlush: 40,
+	}
+	var got [len(want)]int
+	distinct := make(map[Value]bool)
+	var c [5]poker.Card
+	for c[0] = 0; c[0] < poker.NumCards; c[0]++ {
+		for c[1] = c[0] + 1; c[1] < poker.NumCards; c[1]++ {
+			for c[2] = c[1] + 1; c[2] < poker.NumCards; c[2]++ {
+				for c[3] = c[2] + 1; c[3] < poker.NumCards; c[3]++ {
+					for c[4] = c[3] + 1; c[4] < poker.NumCards; c[4]++ {
+						v := Eval(c[:]...)
+						got[v.Category()]++
+						distinct[v] = true
+					}
+				}
+			}
+		}
+	}
+	if got != want {
+		t.Errorf("categories = %v, want %v", got, want)
+	}
+	if len(distinct) != 7462 {
+		t.Errorf("distinct values = %d, want 7462", len(distinct))
+	}
+}
+
+func TestEvalSevenCardsExhaustive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerates all seven card hands")
+	}
+	want := [...]int{
+		HighCard:      23294460,
+		OnePair:       58627800,
+		TwoPair:       31433400,
+		ThreeOfAKind:  6461620,
+		Straight:      6180020,
+		Flush:         4047644,
+		FullHouse:     3473184,
+		FourOfAKind:   224848,
+		StraightFlush: 41584,
+	}
+	var got [len(want)]int
+	var c [7]poker.Card
+	for c[0] = 0; c[0] < poker.NumCards; c[0]++ {
+		for c[1] = c[0] + 1; c[1] < poker.NumCards; c[1]++ {
+			for c[2] = c[1] + 1; c[2] < poker.NumCards; c[2]++ {
+				for c[3] = c[2] + 1; c[3] < poker.NumCards; c[3]++ {
+					for c[4] = c[3] + 1; c[4] < poker.NumCards; c[4]++ {
+						for c[5] = c[4] + 1; c[5] < poker.NumCards; c[5]++ {
+							for c[6] = c[5] + 1; c[6] < poker.NumCards; c[6]++ {
+								got[Eval(c[:]...).Category()]++
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if got != want {
+		t.Errorf("categories = %v, want %v", got, want)
+	}
+}
+
+// bestOfFive ranks the cards by their best five card subset.
+func bestOfFive(cards []poker.Card) Value {
+	var best Value
+	for mask := 0; mask < 1<<len(cards); mask++ {
+		var five []poker.Card
+		for i, c := range cards {
+			if mask&(1<<i) != 0 {
+				five = append(five, c)
+			}
+		}
+		if len(five) == 5 {
+			best = max(best, Eval(five...))
+		}
+	}
+	return best
+}
+
+func TestEvalMatchesBestOfFive(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		cards := randomCards(rnd, 7)
+		for n := 6; n <= 7; n++ {
+			if got, want := Eval(cards[:n]...), bestOfFive(cards[:n]); got != want {
+				t.Fatalf("Eval(%v) = %s, want %s", cards[:n], got, want)
+			}
+		}
+		if got, want := EvalSet(poker.NewCardSet(cards...)), Eval(cards...); got != want {
+			t.Fatalf("EvalSet(%v) = %s, want %s", cards, got, want)
+		}
+	}
+}
+
+func TestEvalHands(t *testing.T) {
+	tests := []struct {
+		cards string
+		want  string
+	}{
+		{"AhKhQhJhTh2c3c", "Straight Flush, Ace high"},
+		{"7h8h9hThJh2h3h", "Straight Flush, Jack high"},
+		{"As2s3s4s5s9d9c", "Straight Flush, Five high"},
+		{"9c9d9h9s2c3d4h", "Four of a Kind, Nines"},
+		{"KhKdKs5c5d2h3c", "Full House, Kings full of Fives"},
+		{"KhKdKs5c5d5h3c", "Full House, Kings full of Fives"},
+		{"Ah9h7h4h2hKdKc", "Flush, Ace high"},
+		{"5h4d3s2cAh9c9d", "Straight, Five high"},
+		{"6c6d6h2s3dJcQd", "Three of a Kind, Sixes"},
+		{"AhAdKcKsQdQh2c", "Two Pair, Aces and Kings"},
+		{"JcJd2h5s8cTdAh", "One Pair, Jacks"},
+		{"2c3d4h5s7c8dTh", "High Card, Ten high"},
+	}
+	for _, tt := range tests {
+		cards, err := poker.ParseCards(tt.cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := Evaluate(cards)
+		if err != nil {
+			t.Fatalf("Evaluate(%s): %v", tt.cards, err)
+		}
+		if v.String() != tt.want {
+			t.Errorf("Evaluate(%s) = %q, want %q", tt.cards, v, tt.want)
+		}
+	}
+}
+
+func TestEvaluateRejects(t *testing.T) {
+	for _, s := range []string{"AhKh", "AhKhQhJhThAh9h", "AhKhQhJhTh9h8h7h"} {
+		cards := make([]poker.Card, 0, len(s)/2)
+		for i := 0; i < len(s); i += 2 {
+			c, _ := poker.ParseCard(s[i : i+2])
+			cards = append(cards, c)
+		}
+		if _, err := Evaluate(cards); err == nil {
+			t.Errorf("Evaluate(%s) accepted", s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"AhAd2c3d4h", "KhKdQcJdTh", 1},
+		{"AhAdKc3d4h", "AsAcQcJdTh", 1},
+		{"5h4d3s2cAh", "6h5d4s3c2h", -1},
+		{"AhKdQcJs9h", "AsKcQdJh9s", 0},
+	}
+	for _, tt := range tests {
+		a, _ := poker.ParseCards(tt.a)
+		b, _ := poker.ParseCards(tt.b)
+		if got := Compare(Eval(a...), Eval(b...)); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func randomCards(rnd *rand.Rand, n int) []poker.Card {
+	cards := make([]poker.Card, n)
+	for i, c := range rnd.Perm(poker.NumCards)[:n] {
+		cards[i] = poker.Card(c)
+	}
+	return cards
+}
+
+func benchmarkHands(n int) [][]poker.Card {
+	rnd := rand.New(rand.NewSource(1))
+	hands := make([][]poker.Card, 1024)
+	for i := range hands {
+		hands[i] = randomCards(rnd, n)
+	}
+	return hands
+}
+
+func BenchmarkEval(b *testing.B) {
+	hands := benchmarkHands(7)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Eval(hands[i%len(hands)]...)
+	}
+}
+
+func BenchmarkEvalSet(b *testing.B) {
+	hands := benchmarkHands(7)
+	sets := make([]poker.CardSet, len(hands))
+	for i, h := range hands {
+		sets[i] = poker.NewCardSet(h...)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EvalSet(sets[i%len(sets)])
+	}
+}
+
+func BenchmarkEvalFive(b *testing.B) {
+	hands := benchmarkHands(5)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Eval(hands[i%len(hands)]...)
+	}
+}
@@ -0,0 +1,176 @@
+package eval
+
+import (
+	"math/bits"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+const (
+	minCards = 5
+	maxCards = 7
+	// maxCount is how many cards of a rank there are.
+	maxCount = poker.NumSuits
+)
+
+var (
+	// flushes values every mask of five or more ranks of a suit.
+	flushes [1 << poker.NumRanks]Value
+	// others values the hands without a flush by the hash of their rank
+	// counts, per number of cards.
+	others [maxCards + 1][]Value
+
+	// ways[l][k] counts the arrays of l rank counts adding up to k.
+	ways [poker.NumRanks + 1][maxCards + 1]int
+	// before[c][l][k] counts those of them starting with a count below c:
+	// the arrays a count array of k cards comes after when its next rank
+	// has c cards and l ranks follow.
+	before [maxCount + 1][poker.NumRanks][maxCards + 1]int
+)
+
+func init() {
+	ways[0][0] = 1
+	for l := 1; l <= poker.NumRanks; l++ {
+		for k := 0; k <= maxCards; k++ {
+			for c := 0; c <= min(maxCount, k); c++ {
+				ways[l][k] += ways[l-1][k-c]
+			}
+		}
+	}
+	for l := 0; l < poker.NumRanks; l++ {
+		for k := 0; k <= maxCards; k++ {
+			for c := 1; c <= maxCount; c++ {
+				before[c][l][k] = before[c-1][l][k]
+				if k-(c-1) >= 0 {
+					before[c][l][k] += ways[l][k-(c-1)]
+				}
+			}
+		}
+	}
+
+	for mask := range flushes {
+		if bits.OnesCount16(uint16(mask)) < 5 {
+			continue
+		}
+		if top, ok := straightTop(uint16(mask)); ok {
+			flushes[mask] = newValue(StraightFlush, top)
+		} else {
+			flushes[mask] = newValue(Flush, topRanks(uint16(mask), 5)...)
+		}
+	}
+
+	for n := minCards; n <= maxCards; n++ {
+		others[n] = make([]Value, ways[poker.NumRanks][n])
+		var counts [poker.NumRanks]uint8
+		fillOthers(&counts, 0, n, n)
+	}
+}
+
+// fillOthers values every count array with the ranks below rank set and
+// left more cards to place.
+func fillOthers(counts *[poker.NumRanks]uint8, rank, left, n int) {
+	if rank == poker.NumRanks {
+		if left == 0 {
+			others[n][hashCounts(counts, n)] = valueOfCounts(counts)
+		}
+		return
+	}
+	for c := 0; c <= min(maxCount, left); c++ {
+		counts[rank] = uint8(c)
+		fillOthers(counts, rank+1, left-c, n)
+	}
+	counts[rank] = 0
+}
+
+// hashCounts is the position of the counts among the count arrays of n
+// cards in lexicographic order, so every array has its own slot.
+func hashCounts(counts *[poker.NumRanks]uint8, n int) int {
+	h, k := 0, n
+	for i := 0; i < poker.NumRanks && k > 0; i++ {
+		h += before[counts[i]][poker.NumRanks-1-i][k]
+		k -= int(counts[i])
+	}
+	return h
+}
+
+// valueOfCounts is the best five card hand of the ranks, suits aside.
+func valueOfCounts(counts *[poker.NumRanks]uint8) Value {
+	var present uint16
+	var quads, trips, pairs []int
+	for r := poker.NumRanks - 1; r >= 0; r-- {
+		switch c := counts[r]; {
+		case c == 4:
+			quads = append(quads, r)
+		case c == 3:
+			trips = append(trips, r)
+		case c == 2:
+			pairs = append(pairs, r)
+		}
+		if counts[r] > 0 {
+			present |= 1 << r
+		}
+	}
+	kickers := func(n int, except ...int) []int {
+		mask := present
+		for _, r := range except {
+			mask &^= 1 << r
+		}
+		return topRanks(mask, n)
+	}
+	if len(quads) > 0 {
+		return newValue(FourOfAKind, append([]int{quads[0]}, kickers(1, quads[0])...)...)
+	}
+	if len(trips) > 0 {
+		// The second trips play as the pair when they beat the pairs.
+		pair := -1
+		if len(trips) > 1 {
+			pair = trips[1]
+		}
+		if len(pairs) > 0 && pairs[0] > pair {
+			pair = pairs[0]
+		}
+		if pair >= 0 {
+			return newValue(FullHouse, trips[0], pair)
+		}
+	}
+	if top, ok := straightTop(present); ok {
+		return newValue(Straight, top)
+	}
+	if len(trips) > 0 {
+		return newValue(ThreeOfAKind, append([]int{trips[0]}, kickers(2, trips[0])...)...)
+	}
+	if len(pairs) > 1 {
+		return newValue(TwoPair, pairs[0], pairs[1], kickers(1, pairs[0], pairs[1])[0])
+	}
+	if len(pairs) == 1 {
+		return newValue(OnePair, append([]int{pairs[0]}, kickers(3, pairs[0])...)...)
+	}
+	return newValue(HighCard, topRanks(present, 5)...)
+}
+
+// straightTop is the top rank of the best straight of the ranks, the wheel
+// is five high.
+func straightTop(mask uint16) (int, bool) {
+	for top := poker.NumRanks - 1; top >= 4; top-- {
+		need := uint16(0x1F) << (top - 4)
+		if mask&need == need {
+			return top, true
+		}
+	}
+	const wheel = 1<<12 | 0xF
+	if mask&wheel == wheel {
+		return 3, true
+	}
+	return 0, false
+}
+
+// topRanks lists the n highest ranks of the mask.
+func topRanks(mask uint16, n int) []int {
+	res := make([]int, 0, n)
+	for r := poker.NumRanks - 1; r >= 0 && len(res) < n; r-- {
+		if mask&(1<<r) != 0 {
+			res = append(res, r)
+		}
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package poker
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+type (
+	// EventRule corrects the detection of recurring events by hand. A rule
+	// matching a series key merges that series into Key, one matching a
+	// part of the tournament title (ByName) splits those tournaments off
+	// into Key.
+	EventRule struct {
+		ID     int64  `json:"id"`
+		Match  string `json:"match"`
+		ByName bool   `json:"by_name"`
+		Key    string `json:"key"`
+	}
+	EventRules []EventRule
+)
+
+var (
+	summaryHeaderRegexp = regexp.MustCompile(`^\s*Tournament\s+#\d+\s*,\s*`)
+	gameSuffixRegexp    = regexp.MustCompile(`(?i),\s*(hold'em|omaha)[^,]*$`)
+	guaranteeRegexp     = regexp.MustCompile(`(?i)[$€¥]?\s*\d[\d,.]*\s*[km]?\s*(gtd|guaranteed)\b`)
+	eventDateRegexp     = regexp.MustCompile(`\b\d{4}[-/.]\d{1,2}[-/.]\d{1,2}\b|\b\d{1,2}[-/]\d{1,2}(?:[-/]\d{2,4})?\b|\b\d{1,2}\.\d{1,2}\.\d{2,4}\b|\b\d{1,2}:\d{2}\b`)
+	eventNumberRegexp   = regexp.MustCompile(`(?i)#\s*\d+|\bno\.?\s*\d+\b`)
+	emptyBracketsRegexp = regexp.MustCompile(`[\[(]\s*[\])]`)
+	eventSpacesRegexp   = regexp.MustCompile(`\s+`)
+)
+
+func (r EventRule) Validate() error {
+	if strings.TrimSpace(r.Match) == "" {
+		return errors.New("rule needs a series key or a tournament name to match")
+	}
+	if strings.TrimSpace(r.Key) == "" {
+		return errors.New("rule needs the series key to count the tournaments under")
+	}
+	if !r.ByName && EventKey(r.Match) == EventKey(r.Key) {
+		return errors.New("series cannot be merged into itself")
+	}
+	return nil
+}
+
+// EventTitle is the name of a tournament without the summary header, the
+// id, and the game.
+func EventTitle(name string) string {
+	name = summaryHeaderRegexp.ReplaceAllString(name, "")
+	name = gameSuffixRegexp.ReplaceAllString(name, "")
+	return strings.TrimSpace(name)
+}
+
+// EventKey identifies a recurring event by its name: the title with the
+// ids, dates, start times and guarantees stripped, lower cased. The buy-in
+// and the table size stay, they tell events apart.
+func EventKey(name string) string {
+	key := EventTitle(name)
+	key = guaranteeRegexp.ReplaceAllString(key, "")
+	key = eventDateRegexp.ReplaceAllString(key, "")
+	key = eventNumberRegexp.ReplaceAllString(key, "")
+	key = emptyBracketsRegexp.ReplaceAllString(key, "")
+	key = eventSpacesRegexp.ReplaceAllString(key, " ")
+	return strings.ToLower(strings.Trim(key, " -|,:"))
+}
+
+// Key is the series of a tournament name after the rules: a split by name
+// wins, then merges are followed.
+func (rs EventRules) Key(name string) string {
+	title := strings.ToLower(EventTitle(name))
+	key := ""
+	for _, r := range rs {
+		if r.ByName && strings.Contains(title, strings.ToLower(strings.TrimSpace(r.Match))) {
+			key = EventKey(r.Key)
+			break
+		}
+	}
+	if key == "" {
+		key = EventKey(name)
+	}
+	// Merges may chain, a loop stops at the key seen twice.
+	seen := map[string]bool{key: true}
+	for {
+		next, ok := rs.merge(key)
+		if !ok || seen[next] {
+			return key
+		}
+		seen[next] = true
+		key = next
+	}
+}
+
+func (rs EventRules) merge(key string) (string, bool) {
+	for _, r := range rs {
+		if !r.ByName && EventKey(r.Match) == key {
+			return EventKey(r.Key), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package poker
+
+import "testing"
+
+func TestEventKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"Tournament #3512345, Bounty Hunters $10.80 [6-Max], Hold'em No Limit", "bounty hunters $10.80 [6-max]"},
+		{"Tournament #3598765, Bounty Hunters $10.80 [6-Max], Hold'em No Limit", "bounty hunters $10.80 [6-max]"},
+		{"Daily Main $22 - $5K GTD 2025-03-01", "daily main $22"},
+		{"Daily Main $22 - $10,000 Gtd 2025/03/02 (19:00)", "daily main $22"},
+		{"Sunday Special #12 $109", "sunday special $109"},
+		{"Sunday Special #12 $215", "sunday special $215"},
+	}
+	for _, tt := range tests {
+		if key := EventKey(tt.name); key != tt.key {
+			t.Errorf("EventKey(%q) = %q, want %q", tt.name, key, tt.key)
+		}
+	}
+}
+
+func TestEventRulesKey(t *testing.T) {
+	rules := EventRules{
+		{Match: "Daily Main $22", Key: "Main Event $22"},
+		{Match: "Main Event $22", Key: "Mains"},
+		{Match: "Turbo", ByName: true, Key: "Daily Turbo $22"},
+		{Match: "loop a", Key: "loop b"},
+		{Match: "loop b", Key: "loop a"},
+	}
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"Daily Main $22 - $5K GTD", "mains"},
+		{"Main Event $22", "mains"},
+		{"Daily Main $22 Turbo", "daily turbo $22"},
+		{"Daily Main $11", "daily main $11"},
+		{"Loop A", "loop b"},
+	}
+	for _, tt := range tests {
+		if key := rules.Key(tt.name); key != tt.key {
+			t.Errorf("Key(%q) = %q, want %q", tt.name, key, tt.key)
+		}
+	}
+}
+
+func TestEventRuleValidate(t *testing.T) {
+	tests := []struct {
+		r  EventRule
+		ok bool
+	}{
+		{EventRule{Match: "Daily Main $22", Key: "Mains"}, true},
+		{EventRule{Match: "Turbo", ByName: true, Key: "Turbo"}, true},
+		{EventRule{Match: "Daily Main $22 #5", Key: "daily main $22"}, false},
+		{EventRule{Match: " ", Key: "Mains"}, false},
+		{EventRule{Match: "Daily Main $22"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.r.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%+v: error %v", tt.r, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package poker
+
+import (
+	"bufio"
+	"regexp"
+	"time"
+)
+
+var (
+	// Poker Hand #TM3391337047: Tournament #183300341, Bounty Hunters Special $2.50 Hold'em No Limit - Level12(300/600(75)) - 2025/01/13 14:02:11
+	handHeaderRegexp = regexp.MustCompile(`Tournament #(\d+),.* - (\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`)
+)
+
+// ScanHandTimes reads a hand history file and keeps in last the time of the
+// latest hand played in every tournament.
+func ScanHandTimes(s *bufio.Scanner, last map[string]time.Time) error {
+	for s.Scan() {
+		match := handHeaderRegexp.FindStringSubmatch(s.Text())
+		if match == nil {
+			continue
+		}
+		played, err := time.Parse(dateLayout, match[2])
+		if err != nil {
+			return err
+		}
+		if played.After(last[match[1]]) {
+			last[match[1]] = played
+		}
+	}
+	return s.Err()
+}
@@ -0,0 +1,238 @@
+package poker
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Hand is a hand of a tournament hand history, with what every seat put
+	// into the pot and won.
+	Hand struct {
+		ID           string    `json:"id"`
+		TournamentID string    `json:"tournament_id"`
+		Played       time.Time `json:"played"`
+		Level        int       `json:"level"`
+		SmallBlind   float64   `json:"small_blind"`
+		BigBlind     float64   `json:"big_blind"`
+		Ante         float64   `json:"ante"`
+		Table        string    `json:"table"`
+		Button       int       `json:"button"`
+		Seats        []Seat    `json:"seats"`
+		Hero         string    `json:"hero"`
+		// Board holds the cards of the first run, as written in the history.
+		Board []string `json:"board,omitempty"`
+		// ActionBoard is how many board cards were out at the last bet or
+		// call, the others were dealt once the betting was over.
+		ActionBoard int `json:"action_board"`
+	}
+
+	Seat struct {
+		Seat  int     `json:"seat"`
+		Name  string  `json:"name"`
+		Stack float64 `json:"stack"`
+		// Cards are the hole cards when they were dealt to the hero or shown.
+		Cards []string `json:"cards,omitempty"`
+		// Put is what went into the pot, uncalled bets returned.
+		Put    float64 `json:"put"`
+		Won    float64 `json:"won"`
+		AllIn  bool    `json:"all_in"`
+		Folded bool    `json:"folded"`
+	}
+)
+
+var (
+	// Poker Hand #TM3391337047: Tournament #183300341, Bounty Hunters Special $2.50 Hold'em No Limit - Level12(300/600(75)) - 2025/01/13 14:02:11
+	handStartRegexp   = regexp.MustCompile(`^Poker Hand #(\w+): Tournament #(\d+),.* - Level(\d+)\(([\d,.]+)/([\d,.]+)(?:\(([\d,.]+)\))?\) - (\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`)
+	handTableRegexp   = regexp.MustCompile(`^Table '([^']*)'.* Seat #(\d+) is the button`)
+	handSeatRegexp    = regexp.MustCompile(`^Seat (\d+): (.+) \(([\d,.]+) in chips`)
+	handDealtRegexp   = regexp.MustCompile(`^Dealt to (.+?) \[(.+)\]`)
+	handActionRegexp  = regexp.MustCompile(`^(.+?): (posts the ante|posts small blind|posts big blind|posts straddle|bets|calls|raises) ([\d,.]+)(?: to ([\d,.]+))?`)
+	handFoldRegexp    = regexp.MustCompile(`^(.+?): folds`)
+	handShowsRegexp   = regexp.MustCompile(`^(.+?): shows \[(.+?)\]`)
+	handUncalledRegex = regexp.MustCompile(`^Uncalled bet \(([\d,.]+)\) returned to (.+)$`)
+	handWonRegexp     = regexp.MustCompile(`^(.+?) collected ([\d,.]+) from`)
+	handStreetRegexp  = regexp.MustCompile(`^\*\*\* (FIRST |SECOND )?(FLOP|TURN|RIVER) \*\*\*`)
+	handCardsRegexp   = regexp.MustCompile(`\[([^\]]+)\]`)
+)
+
+// ParseHands reads the tournament hands of a hand history file.
+func ParseHands(s *bufio.Scanner) ([]Hand, error) {
+	var hands []Hand
+	var h *Hand
+	// street is what every seat has bet on the current street.
+	street := make(map[string]float64)
+	inSummary := false
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if match := handStartRegexp.FindStringSubmatch(line); match != nil {
+			played, err := time.Parse(dateLayout, match[7])
+			if err != nil {
+				return nil, err
+			}
+			hands = append(hands, Hand{
+				ID:           match[1],
+				TournamentID: match[2],
+				Played:       played,
+				Level:        atoi(match[3]),
+				SmallBlind:   chips(match[4]),
+				BigBlind:     chips(match[5]),
+				Ante:         chips(match[6]),
+			})
+			h = &hands[len(hands)-1]
+			clear(street)
+			inSummary = false
+			continue
+		}
+		if h == nil || inSummary {
+			continue
+		}
+		if line == "*** SUMMARY ***" {
+			inSummary = true
+			continue
+		}
+		if match := handTableRegexp.FindStringSubmatch(line); match != nil {
+			h.Table, h.Button = match[1], atoi(match[2])
+			continue
+		}
+		if match := handSeatRegexp.FindStringSubmatch(line); match != nil {
+			h.Seats = append(h.Seats, Seat{Seat: atoi(match[1]), Name: match[2], Stack: chips(match[3])})
+			continue
+		}
+		if match := handDealtRegexp.FindStringSubmatch(line); match != nil {
+			h.Hero = match[1]
+			if seat := h.seat(match[1]); seat != nil {
+				seat.Cards = strings.Fields(match[2])
+			}
+			continue
+		}
+		if match := handStreetRegexp.FindStringSubmatch(line); match != nil {
+			clear(street)
+			if match[1] != "SECOND " {
+				h.Board = nil
+				for _, cards := range handCardsRegexp.FindAllStringSubmatch(line, -1) {
+					h.Board = append(h.Board, strings.Fields(cards[1])...)
+				}
+			}
+			continue
+		}
+		if match := handActionRegexp.FindStringSubmatch(line); match != nil {
+			seat := h.seat(match[1])
+			if seat == nil {
+				continue
+			}
+			amount := chips(match[3])
+			switch match[2] {
+			case "posts the ante":
+				seat.Put += amount
+			case "raises":
+				to := chips(match[4])
+				seat.Put += to - street[seat.Name]
+				street[seat.Name] = to
+			default:
+				seat.Put += amount
+				street[seat.Name] += amount
+			}
+			if !strings.HasPrefix(match[2], "posts") {
+				h.ActionBoard = len(h.Board)
+			}
+			if strings.HasSuffix(line, "and is all-in") {
+				seat.AllIn = true
+			}
+			continue
+		}
+		if match := handFoldRegexp.FindStringSubmatch(line); match != nil {
+			if seat := h.seat(match[1]); seat != nil {
+				seat.Folded = true
+			}
+			continue
+		}
+		if match := handShowsRegexp.FindStringSubmatch(line); match != nil {
+			if seat := h.seat(match[1]); seat != nil {
+				seat.Cards = strings.Fields(match[2])
+			}
+			continue
+		}
+		if match := handUncalledRegex.FindStringSubmatch(line); match != nil {
+			if seat := h.seat(match[2]); seat != nil {
+				seat.Put -= chips(match[1])
+			}
+			continue
+		}
+		if match := handWonRegexp.FindStringSubmatch(line); match != nil {
+			if seat := h.seat(match[1]); seat != nil {
+				seat.Won += chips(match[2])
+			}
+		}
+	}
+	return hands, s.Err()
+}
+
+func (h *Hand) seat(name string) *Seat {
+	for i := range h.Seats {
+		if h.Seats[i].Name == name {
+			return &h.Seats[i]
+		}
+	}
+	return nil
+}
+
+// AllIn tells whether a player put all their chips in.
+func (h Hand) AllIn() bool {
+	for _, s := range h.Seats {
+		if s.AllIn {
+			return true
+		}
+	}
+	return false
+}
+
+// StacksBefore are the stacks at the start of the hand, by seat order.
+func (h Hand) StacksBefore() []float64 {
+	res := make([]float64, len(h.Seats))
+	for i, s := range h.Seats {
+		res[i] = s.Stack
+	}
+	return res
+}
+
+// StacksAfter are the stacks at the end of the hand, by seat order.
+func (h Hand) StacksAfter() []float64 {
+	res := make([]float64, len(h.Seats))
+	for i, s := range h.Seats {
+		res[i] = max(0, s.Stack-s.Put+s.Won)
+	}
+	return res
+}
+
+// HeroSeat is the index of the hero in Seats, -1 when the hero is unknown.
+func (h Hand) HeroSeat() int {
+	for i, s := range h.Seats {
+		if s.Name == h.Hero {
+			return i
+		}
+	}
+	return -1
+}
+
+// HoleCards are the cards of the seat, ok is false unless both are known.
+func (s Seat) HoleCards() (HoleCards, bool) {
+	if len(s.Cards) != 2 {
+		return HoleCards{}, false
+	}
+	h, err := ParseHoleCards(s.Cards[0] + s.Cards[1])
+	return h, err == nil
+}
+
+func chips(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	return v
+}
+
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
@@ -0,0 +1,109 @@
+// Package icm values tournament chip stacks in prize money with the
+// Independent Chip Model of Malmuth and Harville: a player finishes first
+// with the probability of their share of the chips, and the places below are
+// dealt the same way among the others.
+package icm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxPlayers bounds the model, its cost doubles with every player.
+const MaxPlayers = 20
+
+// Result is the value of every stack, in the order given.
+type Result struct {
+	// Equity is the expected prize of every player.
+	Equity []float64 `json:"equity"`
+	// Places are the chances of every player to finish at each place, from
+	// the first one down.
+	Places [][]float64 `json:"places"`
+}
+
+// Equity values the stacks for the prizes of the places from the first
+// one down; missing prizes are zero. Players without chips are out, they
+// share the prizes of the places below the others.
+func Equity(stacks, prizes []float64) (Result, error) {
+	n := len(stacks)
+	if n == 0 {
+		return Result{}, errors.New("no stacks")
+	}
+	if n > MaxPlayers {
+		return Result{}, fmt.Errorf("at most %d players are supported", MaxPlayers)
+	}
+	prize := func(place int) float64 {
+		if place < len(prizes) {
+			return prizes[place]
+		}
+		return 0
+	}
+	var alive []int
+	var busted []int
+	for i, s := range stacks {
+		switch {
+		case s < 0:
+			return Result{}, fmt.Errorf("stack %d is negative", i+1)
+		case s == 0:
+			busted = append(busted, i)
+		default:
+			alive = append(alive, i)
+		}
+	}
+	if len(alive) == 0 {
+		return Result{}, errors.New("nobody has chips")
+	}
+
+	res := Result{Equity: make([]float64, n), Places: make([][]float64, n)}
+	for i := range res.Places {
+		res.Places[i] = make([]float64, n)
+	}
+	// The busted players tie below the others.
+	for _, i := range busted {
+		for place := len(alive); place < n; place++ {
+			share := 1 / float64(len(busted))
+			res.Places[i][place] = share
+			res.Equity[i] += share * prize(place)
+		}
+	}
+
+	// prob[mask] is the chance that the players of mask took the places
+	// above the others, in any order. Every mask is reached from smaller
+	// ones, so a single pass in increasing order fills them all.
+	m := len(alive)
+	var total float64
+	chips := make([]float64, m)
+	for k, i := range alive {
+		chips[k] = stacks[i]
+		total += stacks[i]
+	}
+	sums := make([]float64, 1<<m)
+	prob := make([]float64, 1<<m)
+	prob[0] = 1
+	for mask := 0; mask < len(prob); mask++ {
+		if prob[mask] == 0 {
+			continue
+		}
+		place := 0
+		for k := 0; k < m; k++ {
+			if mask&(1<<k) != 0 {
+				place++
+			}
+		}
+		left := total - sums[mask]
+		for k := 0; k < m; k++ {
+			bit := 1 << k
+			if mask&bit != 0 {
+				continue
+			}
+			p := prob[mask] * chips[k] / left
+			next := mask | bit
+			prob[next] += p
+			sums[next] = sums[mask] + chips[k]
+			i := alive[k]
+			res.Places[i][place] += p
+			res.Equity[i] += p * prize(place)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package icm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEquity(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []float64
+		prizes []float64
+		want   []float64
+	}{
+		{"three handed", []float64{50, 30, 20}, []float64{50, 30, 20}, []float64{38.393, 32.75, 28.857}},
+		{"winner takes all", []float64{75, 25}, []float64{100}, []float64{75, 25}},
+		{"equal stacks", []float64{10, 10, 10, 10}, []float64{50, 30, 20}, []float64{25, 25, 25, 25}},
+		{"busted player takes the last place", []float64{100, 0, 100}, []float64{50, 30, 20}, []float64{40, 20, 40}},
+		{"busted players split the places below", []float64{100, 0, 0}, []float64{50, 30, 20}, []float64{50, 25, 25}},
+		{"prizes beyond the players", []float64{1, 1}, []float64{60, 40, 20}, []float64{50, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Equity(tt.stacks, tt.prizes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				if math.Abs(res.Equity[i]-want) > 0.001 {
+					t.Errorf("equity[%d] = %.4f, want %.4f", i, res.Equity[i], want)
+				}
+			}
+			for i, places := range res.Places {
+				var sum float64
+				for _, p := range places {
+					sum += p
+				}
+				if math.Abs(sum-1) > 1e-9 {
+					t.Errorf("places of player %d add up to %f", i, sum)
+				}
+			}
+		})
+	}
+}
+
+func TestEquityKeepsThePrizePool(t *testing.T) {
+	stacks := []float64{12, 7, 31, 4, 18, 9, 22, 15, 3}
+	prizes := []float64{40, 25, 15, 10, 6, 4}
+	res, err := Equity(stacks, prizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for i, e := range res.Equity {
+		total += e
+		if i > 0 && stacks[i] > stacks[i-1] && e <= res.Equity[i-1] {
+			t.Errorf("bigger stack %d is worth less than stack %d", i, i-1)
+		}
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Errorf("equity adds up to %f, want 100", total)
+	}
+}
+
+func TestEquityRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []float64
+	}{
+		{"no stacks", nil},
+		{"negative stack", []float64{10, -1}},
+		{"no chips", []float64{0, 0}},
+		{"too many players", make([]float64, MaxPlayers+1)},
+	}
+	for _, tt := range tests {
+		if _, err := Equity(tt.stacks, []float64{100}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
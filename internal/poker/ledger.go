@@ -0,0 +1,75 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// Transaction is a bankroll movement not coming from a tournament result.
+	Transaction struct {
+		ID       int64           `json:"id"`
+		Type     TransactionType `json:"type"`
+		Amount   float32         `json:"amount"`
+		Currency Currency        `json:"currency"`
+		Date     time.Time       `json:"date"`
+		Room     string          `json:"room"`
+		Note     string          `json:"note"`
+		PlayerID int64           `json:"player_id"`
+	}
+	TransactionType string
+)
+
+const (
+	Deposit     TransactionType = "deposit"
+	Withdrawal  TransactionType = "withdrawal"
+	Rakeback    TransactionType = "rakeback"
+	Bonus       TransactionType = "bonus"
+	TransferIn  TransactionType = "transfer_in"
+	TransferOut TransactionType = "transfer_out"
+	// Adjustment keeps the sign of its amount.
+	Adjustment TransactionType = "adjustment"
+)
+
+var TransactionTypes = []TransactionType{
+	Deposit, Withdrawal, Rakeback, Bonus, TransferIn, TransferOut, Adjustment,
+}
+
+func ParseTransactionType(s string) (TransactionType, error) {
+	for _, t := range TransactionTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown transaction type %q", s)
+}
+
+// Signed is the amount as it changes the bankroll.
+func (t Transaction) Signed() float32 {
+	switch t.Type {
+	case Withdrawal, TransferOut:
+		return -t.Amount
+	}
+	return t.Amount
+}
+
+func (t Transaction) SignedDollars() float32 {
+	return t.Currency.ToDollar(t.Signed())
+}
+
+// Validate checks a transaction before it is stored.
+func (t Transaction) Validate() error {
+	if _, err := ParseTransactionType(string(t.Type)); err != nil {
+		return err
+	}
+	if _, err := ParseCurrency(string(t.Currency)); err != nil {
+		return err
+	}
+	if t.Type != Adjustment && t.Amount <= 0 {
+		return fmt.Errorf("amount of %s must be positive", t.Type)
+	}
+	if t.Date.IsZero() {
+		return fmt.Errorf("date is required")
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package poker
+
+import (
+	"errors"
+	"math"
+)
+
+type (
+	// PayoutStructure is a known payout table. It applies to the tournaments
+	// of the Event series or of the Type, to all when both are empty, with
+	// a field within MinPlayers..MaxPlayers (zero for no bound).
+	PayoutStructure struct {
+		ID         int64          `json:"id"`
+		Name       string         `json:"name"`
+		Type       TournamentType `json:"type,omitempty"`
+		Event      string         `json:"event,omitempty"`
+		MinPlayers int            `json:"min_players,omitempty"`
+		MaxPlayers int            `json:"max_players,omitempty"`
+		// Places are the percents of the prize pool paid from the first place down.
+		Places []float64 `json:"places"`
+	}
+
+	// Payout is the payout table of a tournament.
+	Payout struct {
+		Paid int `json:"paid"`
+		// Prizes are the amounts paid from the first place down.
+		Prizes  []float64 `json:"prizes"`
+		MinCash float64   `json:"min_cash"`
+		// StructureID is the stored structure used, zero when estimated.
+		StructureID int64 `json:"structure_id,omitempty"`
+		Estimated   bool  `json:"estimated"`
+	}
+
+	// PayoutBook picks the payout of a tournament from the stored structures
+	// and estimates the others. The zero value estimates everything.
+	PayoutBook struct {
+		Structures []PayoutStructure
+		Events     EventRules
+	}
+)
+
+// estimatedPaidShare is the typical part of the field that gets paid.
+const estimatedPaidShare = 0.15
+
+func (s PayoutStructure) Validate() error {
+	if s.Name == "" {
+		return errors.New("structure name is required")
+	}
+	if s.MinPlayers < 0 || s.MaxPlayers < 0 || (s.MaxPlayers > 0 && s.MaxPlayers < s.MinPlayers) {
+		return errors.New("players range is invalid")
+	}
+	if len(s.Places) == 0 {
+		return errors.New("structure needs paid places")
+	}
+	var total float64
+	for i, p := range s.Places {
+		if p <= 0 {
+			return errors.New("places must pay a positive percent")
+		}
+		if i > 0 && p > s.Places[i-1] {
+			return errors.New("places cannot pay more than the place above")
+		}
+		total += p
+	}
+	// Published tables are rounded.
+	if math.Abs(total-100) > 1 {
+		return errors.New("places must add up to 100 percent")
+	}
+	return nil
+}
+
+// matches scores how well the structure fits the tournament of the event,
+// ok is false when it does not apply.
+func (s PayoutStructure) matches(t Tournament, event string) (int, bool) {
+	if t.Players < s.MinPlayers || (s.MaxPlayers > 0 && t.Players > s.MaxPlayers) {
+		return 0, false
+	}
+	score := 0
+	if s.Event != "" {
+		if EventKey(s.Event) != event {
+			return 0, false
+		}
+		score += 2
+	}
+	if s.Type != "" {
+		if s.Type != t.Type {
+			return 0, false
+		}
+		score++
+	}
+	return score, true
+}
+
+// Payout is the payout table of the tournament: the best fitting stored
+// structure, the one for its series over the one for its type, or an
+// estimate.
+func (b PayoutBook) Payout(t Tournament) Payout {
+	event := b.Events.Key(t.Name)
+	best, bestScore := -1, -1
+	for i, s := range b.Structures {
+		if score, ok := s.matches(t, event); ok && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return EstimatePayout(t)
+	}
+	s := b.Structures[best]
+	places := s.Places
+	if t.Players > 0 && len(places) > t.Players {
+		places = places[:t.Players]
+	}
+	p := newPayout(t, places)
+	p.StructureID = s.ID
+	return p
+}
+
+// EstimatePayout pays the usual part of the field with prizes falling as
+// 1/place, which is close to the tables of the rooms: about 65/35 for two
+// paid places and a min-cash of one to two buy-ins in large fields.
+func EstimatePayout(t Tournament) Payout {
+	paid := max(1, int(math.Ceil(float64(t.Players)*estimatedPaidShare)))
+	if t.Players > 0 {
+		paid = min(paid, t.Players)
+	}
+	places := make([]float64, paid)
+	var total float64
+	for i := range places {
+		places[i] = 1 / float64(i+1)
+		total += places[i]
+	}
+	for i := range places {
+		places[i] *= 100 / total
+	}
+	p := newPayout(t, places)
+	p.Estimated = true
+	return p
+}
+
+func newPayout(t Tournament, places []float64) Payout {
+	p := Payout{Paid: len(places), Prizes: make([]float64, len(places))}
+	for i, percent := range places {
+		p.Prizes[i] = float64(t.TotalPrizePool) * percent / 100
+	}
+	p.MinCash = p.Prizes[len(p.Prizes)-1]
+	return p
+}
+
+// Prize is what the place pays, zero out of the money.
+func (p Payout) Prize(place int) float64 {
+	if place < 1 || place > len(p.Prizes) {
+		return 0
+	}
+	return p.Prizes[place-1]
+}
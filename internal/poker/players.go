@@ -0,0 +1,51 @@
+package poker
+
+import (
+	"errors"
+	"strings"
+)
+
+type (
+	// Player is a member of the team, playing on one or more accounts.
+	Player struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// Account is a screen name of a player in a room.
+	Account struct {
+		ID         int64  `json:"id"`
+		PlayerID   int64  `json:"player_id"`
+		Room       string `json:"room"`
+		ScreenName string `json:"screen_name"`
+		// SourceDir holds the summaries of the account, relative to DB_BASE_DIR.
+		SourceDir string `json:"source_dir,omitempty"`
+	}
+)
+
+func (p Player) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("player name is required")
+	}
+	return nil
+}
+
+func (a Account) Validate() error {
+	if a.PlayerID <= 0 {
+		return errors.New("account needs a player")
+	}
+	if strings.TrimSpace(a.Room) == "" || strings.TrimSpace(a.ScreenName) == "" {
+		return errors.New("account needs a room and a screen name")
+	}
+	return nil
+}
+
+// String is the label of the account in reports.
+func (a Account) String() string {
+	return a.Room + "/" + a.ScreenName
+}
+
+// Matches tells whether a tournament played as screenName in room belongs to the account.
+func (a Account) Matches(room, screenName string) bool {
+	return strings.EqualFold(a.Room, room) && strings.EqualFold(a.ScreenName, screenName)
+}
@@ -0,0 +1,56 @@
+package poker
+
+import (
+	"errors"
+	"sort"
+)
+
+type (
+	// RakebackRule describes a monthly rakeback or cashback deal. The whole
+	// month is paid at the percentage of the highest tier reached by the rake
+	// paid in it, or at Percent below the first tier.
+	RakebackRule struct {
+		ID      int64          `json:"id"`
+		Name    string         `json:"name"`
+		Room    string         `json:"room"`
+		Percent float64        `json:"percent"`
+		Tiers   []RakebackTier `json:"tiers"`
+	}
+
+	RakebackTier struct {
+		// Threshold is the monthly rake in $ needed for the tier.
+		Threshold float64 `json:"threshold"`
+		Percent   float64 `json:"percent"`
+	}
+)
+
+func (r RakebackRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule name is required")
+	}
+	if r.Percent < 0 || r.Percent > 100 {
+		return errors.New("percent must be within 0..100")
+	}
+	for _, t := range r.Tiers {
+		if t.Threshold < 0 || t.Percent < 0 || t.Percent > 100 {
+			return errors.New("tiers need a non negative threshold and a percent within 0..100")
+		}
+	}
+	return nil
+}
+
+// PercentFor returns the percentage earned for the given monthly rake.
+func (r RakebackRule) PercentFor(rake float64) float64 {
+	tiers := make([]RakebackTier, len(r.Tiers))
+	copy(tiers, r.Tiers)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Threshold < tiers[j].Threshold
+	})
+	percent := r.Percent
+	for _, t := range tiers {
+		if rake >= t.Threshold {
+			percent = t.Percent
+		}
+	}
+	return percent
+}
@@ -0,0 +1,391 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// HoleCards are the two private cards of a player, the higher card
+	// first so a combo has a single form.
+	HoleCards [2]Card
+	// Range weights the hole cards a player may hold, from 0 to 1 for the
+	// part of the combo played. The combos of a range are its keys, it is
+	// written in the usual notation, see ParseRange.
+	Range map[HoleCards]float64
+)
+
+// NumCombos is the number of distinct hole cards.
+const NumCombos = NumCards * (NumCards - 1) / 2
+
+func NewHoleCards(a, b Card) HoleCards {
+	if a < b {
+		a, b = b, a
+	}
+	return HoleCards{a, b}
+}
+
+// ParseHoleCards reads two cards, e.g. AhKd.
+func ParseHoleCards(s string) (HoleCards, error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return HoleCards{}, err
+	}
+	if len(cards) != 2 {
+		return HoleCards{}, fmt.Errorf("invalid hole cards %q", s)
+	}
+	return NewHoleCards(cards[0], cards[1]), nil
+}
+
+func (h HoleCards) Set() CardSet {
+	return NewCardSet(h[0], h[1])
+}
+
+func (h HoleCards) String() string {
+	return h[0].String() + h[1].String()
+}
+
+// ParseRange reads a range in the usual notation, parts separated by
+// commas or spaces:
+//
+//	QQ, AKs, KTo, AK  a pair, suited, offsuit or any hand of the ranks
+//	22+, A2s+, KTo+   the hand and those above it, up to AA or to the
+//	                  kicker below the top card
+//	QQ-88, A5s-A2s    the hands between the two
+//	AhKd              a single combo
+//
+// A part may end with the weight of its combos, AKs:0.5 plays half of
+// them; a later part overrides the weight of an earlier one and weight 0
+// removes the combos.
+func ParseRange(s string) (Range, error) {
+	r := Range{}
+	for _, part := range strings.FieldsFunc(s, isRangeSeparator) {
+		notation, weight := part, 1.0
+		if i := strings.IndexByte(part, ':'); i >= 0 {
+			w, err := strconv.ParseFloat(part[i+1:], 64)
+			if err != nil || w < 0 || w > 1 {
+				return nil, fmt.Errorf("invalid weight in %q, it must be within 0..1", part)
+			}
+			notation, weight = part[:i], w
+		}
+		combos, err := parseRangePart(notation)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range combos {
+			if weight == 0 {
+				delete(r, h)
+			} else {
+				r[h] = weight
+			}
+		}
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("empty range %q", s)
+	}
+	return r, nil
+}
+
+func isRangeSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t' || r == '\n'
+}
+
+// handClass is a starting hand regardless of the suits, a pair when hi
+// equals lo. Offsuit classes are not suited, any classes are both.
+type handClass struct {
+	hi, lo  int
+	suited  bool
+	offsuit bool
+}
+
+func parseHandClass(s string) (handClass, error) {
+	if len(s) != 2 && len(s) != 3 {
+		return handClass{}, fmt.Errorf("invalid hand %q", s)
+	}
+	hi, ok1 := ParseRank(s[0])
+	lo, ok2 := ParseRank(s[1])
+	if !ok1 || !ok2 {
+		return handClass{}, fmt.Errorf("invalid hand %q", s)
+	}
+	if hi < lo {
+		hi, lo = lo, hi
+	}
+	c := handClass{hi: hi, lo: lo, suited: true, offsuit: true}
+	if len(s) == 3 {
+		switch {
+		case hi == lo:
+			return handClass{}, fmt.Errorf("pair %q cannot be suited or offsuit", s)
+		case lower(s[2]) == 's':
+			c.offsuit = false
+		case lower(s[2]) == 'o':
+			c.suited = false
+		default:
+			return handClass{}, fmt.Errorf("invalid hand %q", s)
+		}
+	}
+	return c, nil
+}
+
+func (c handClass) pair() bool {
+	return c.hi == c.lo
+}
+
+func (c handClass) combos() []HoleCards {
+	var res []HoleCards
+	for s1 := 0; s1 < NumSuits; s1++ {
+		for s2 := 0; s2 < NumSuits; s2++ {
+			switch {
+			case c.pair() && s2 <= s1:
+			case !c.pair() && s1 == s2 && !c.suited:
+			case !c.pair() && s1 != s2 && !c.offsuit:
+			default:
+				res = append(res, NewHoleCards(NewCard(c.hi, s1), NewCard(c.lo, s2)))
+			}
+		}
+	}
+	return res
+}
+
+func (c handClass) String() string {
+	s := string([]byte{RankChar(c.hi), RankChar(c.lo)})
+	switch {
+	case c.pair() || (c.suited && c.offsuit):
+		return s
+	case c.suited:
+		return s + "s"
+	}
+	return s + "o"
+}
+
+func parseRangePart(s string) ([]HoleCards, error) {
+	if len(s) == 4 && !strings.ContainsAny(s, "+-") {
+		if h, err := ParseHoleCards(s); err == nil {
+			return []HoleCards{h}, nil
+		}
+	}
+	var classes []handClass
+	switch {
+	case strings.HasSuffix(s, "+"):
+		c, err := parseHandClass(strings.TrimSuffix(s, "+"))
+		if err != nil {
+			return nil, err
+		}
+		top := c.hi - 1
+		if c.pair() {
+			top = NumRanks - 1
+		}
+		classes = classesBetween(c, c.lo, top)
+	case strings.Contains(s, "-"):
+		from, to, _ := strings.Cut(s, "-")
+		a, err := parseHandClass(from)
+		if err != nil {
+			return nil, err
+		}
+		b, err := parseHandClass(to)
+		if err != nil {
+			return nil, err
+		}
+		if a.pair() != b.pair() || a.suited != b.suited || a.offsuit != b.offsuit || (!a.pair() && a.hi != b.hi) {
+			return nil, fmt.Errorf("invalid span %q, the hands must differ in the lower card only", s)
+		}
+		classes = classesBetween(a, min(a.lo, b.lo), max(a.lo, b.lo))
+	default:
+		c, err := parseHandClass(s)
+		if err != nil {
+			return nil, err
+		}
+		classes = []handClass{c}
+	}
+	var res []HoleCards
+	for _, c := range classes {
+		res = append(res, c.combos()...)
+	}
+	return res, nil
+}
+
+// classesBetween lists the classes like c with the lower rank from lo to
+// hi, both ranks move for pairs.
+func classesBetween(c handClass, lo, hi int) []handClass {
+	var res []handClass
+	for rank := lo; rank <= hi; rank++ {
+		next := c
+		next.lo = rank
+		if c.pair() {
+			next.hi = rank
+		}
+		res = append(res, next)
+	}
+	return res
+}
+
+// Combos lists the combos of the range, the highest first.
+func (r Range) Combos() []HoleCards {
+	res := make([]HoleCards, 0, len(r))
+	for h, w := range r {
+		if w > 0 {
+			res = append(res, h)
+		}
+	}
+	sortCombos(res)
+	return res
+}
+
+func sortCombos(combos []HoleCards) {
+	sort.Slice(combos, func(i, j int) bool {
+		if combos[i][0] != combos[j][0] {
+			return combos[i][0] > combos[j][0]
+		}
+		return combos[i][1] > combos[j][1]
+	})
+}
+
+// Count is the number of combos in the range, counted by their weights.
+func (r Range) Count() float64 {
+	var n float64
+	for _, w := range r {
+		n += w
+	}
+	return n
+}
+
+// Percent is the part of all the starting hands the range plays.
+func (r Range) Percent() float64 {
+	return 100 * r.Count() / NumCombos
+}
+
+// Union holds the combos of both ranges, with the greater weight.
+func (r Range) Union(o Range) Range {
+	res := make(Range, len(r)+len(o))
+	for h, w := range r {
+		res[h] = w
+	}
+	for h, w := range o {
+		res[h] = max(res[h], w)
+	}
+	return res
+}
+
+// Intersect holds the combos in both ranges, with the smaller weight.
+func (r Range) Intersect(o Range) Range {
+	res := Range{}
+	for h, w := range r {
+		if ow, ok := o[h]; ok {
+			res[h] = min(w, ow)
+		}
+	}
+	return res
+}
+
+// Without drops the combos blocked by the cards, e.g. the board.
+func (r Range) Without(cards CardSet) Range {
+	res := Range{}
+	for h, w := range r {
+		if h.Set()&cards == 0 {
+			res[h] = w
+		}
+	}
+	return res
+}
+
+// String writes the range in the notation read by ParseRange, hands
+// grouped where all their combos share a weight: pairs first, then the
+// suited and offsuit hands by their top card, then the single combos.
+func (r Range) String() string {
+	var parts []string
+	covered := map[HoleCards]bool{}
+	spans := func(classes []handClass) {
+		for i := 0; i < len(classes); {
+			w, ok := r.classWeight(classes[i])
+			if !ok {
+				i++
+				continue
+			}
+			j := i
+			for j+1 < len(classes) {
+				if next, ok := r.classWeight(classes[j+1]); !ok || next != w {
+					break
+				}
+				j++
+			}
+			var part string
+			switch {
+			case i == 0 && j > i:
+				part = classes[j].String() + "+"
+			case j > i:
+				part = classes[i].String() + "-" + classes[j].String()
+			default:
+				part = classes[i].String()
+			}
+			parts = append(parts, part+weightSuffix(w))
+			for _, c := range classes[i : j+1] {
+				for _, h := range c.combos() {
+					covered[h] = true
+				}
+			}
+			i = j + 1
+		}
+	}
+
+	var pairs []handClass
+	for rank := NumRanks - 1; rank >= 0; rank-- {
+		pairs = append(pairs, handClass{hi: rank, lo: rank, suited: true, offsuit: true})
+	}
+	spans(pairs)
+	for _, suited := range []bool{true, false} {
+		for hi := NumRanks - 1; hi > 0; hi-- {
+			var classes []handClass
+			for lo := hi - 1; lo >= 0; lo-- {
+				classes = append(classes, handClass{hi: hi, lo: lo, suited: suited, offsuit: !suited})
+			}
+			spans(classes)
+		}
+	}
+	for _, h := range r.Combos() {
+		if !covered[h] {
+			parts = append(parts, h.String()+weightSuffix(r[h]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// classWeight is the weight shared by all the combos of the class, ok is
+// false when they do not share one.
+func (r Range) classWeight(c handClass) (float64, bool) {
+	var w float64
+	for i, h := range c.combos() {
+		hw, ok := r[h]
+		if !ok || hw <= 0 || (i > 0 && hw != w) {
+			return 0, false
+		}
+		w = hw
+	}
+	return w, true
+}
+
+func weightSuffix(w float64) string {
+	if w == 1 {
+		return ""
+	}
+	return ":" + strconv.FormatFloat(w, 'g', -1, 64)
+}
+
+// MarshalJSON writes the range as its notation.
+func (r Range) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Range) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseRange(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,127 @@
+package poker
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in     string
+		out    string
+		combos float64
+	}{
+		{"22+, A2s+, KTo+, 65s", "22+, A2s+, 65s, KTo+", 166},
+		{"AA", "AA", 6},
+		{"AK", "AKs, AKo", 16},
+		{"KA", "AKs, AKo", 16},
+		{"QQ-88, A5s-A2s:0.5", "QQ-88, A5s-A2s:0.5", 38},
+		{"99-JJ", "JJ-99", 18},
+		{"22-AA", "22+", 78},
+		{"JJ+, AKs, AKo:0.25, 76s", "JJ+, AKs, 76s, AKo:0.25", 35},
+		{"TT+, TT:0", "JJ+", 24},
+		{"AKs, AhKh:0", "AsKs, AdKd, AcKc", 3},
+		{"AhKd, AsKs", "AsKs, AhKd", 2},
+		{"K9s+ KhTd", "K9s+, KhTd", 17},
+		{"ako", "AKo", 12},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.in)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", tt.in, err)
+			continue
+		}
+		if got := r.String(); got != tt.out {
+			t.Errorf("ParseRange(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+		if got := r.Count(); got != tt.combos {
+			t.Errorf("ParseRange(%q) has %v combos, want %v", tt.in, got, tt.combos)
+		}
+		back, err := ParseRange(r.String())
+		if err != nil || !sameRange(back, r) {
+			t.Errorf("%q does not read back: %v, %v", r.String(), back, err)
+		}
+	}
+}
+
+func TestParseRangeRejects(t *testing.T) {
+	for _, s := range []string{"", "AAs", "AK+-", "A5s-K2s", "QQ-AKs", "AKs:2", "AKs:-1", "XX", "AhAh", "AKx"} {
+		if r, err := ParseRange(s); err == nil {
+			t.Errorf("ParseRange(%q) = %v, want an error", s, r)
+		}
+	}
+}
+
+func TestRangeAlgebra(t *testing.T) {
+	a := mustRange(t, "QQ+, AK")
+	b := mustRange(t, "KK+:0.5, AKs, 22")
+	tests := []struct {
+		name string
+		got  Range
+		want string
+	}{
+		{"union", a.Union(b), "QQ+, 22, AKs, AKo"},
+		{"intersection", a.Intersect(b), "KK+:0.5, AKs"},
+		{"blockers", mustRange(t, "AA, KK").Without(NewCardSet(mustCards(t, "Ah Kd")...)), "AsAd, AsAc, AdAc, KsKh, KsKc, KhKc"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := mustRange(t, "22+, A2s+, K2s+, Q2s+").Percent(); math.Abs(got-15.84) > 0.01 {
+		t.Errorf("percent = %.2f, want 15.84", got)
+	}
+}
+
+func TestRangeJSON(t *testing.T) {
+	r := mustRange(t, "QQ+, AKs, AKo:0.5")
+	data, err := json.Marshal(map[string]Range{"range": r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"range":"QQ+, AKs, AKo:0.5"}` {
+		t.Errorf("json = %s", data)
+	}
+	var back map[string]Range
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !sameRange(back["range"], r) {
+		t.Errorf("read back %v, want %v", back["range"], r)
+	}
+	if err := json.Unmarshal([]byte(`{"range":"ZZ"}`), &back); err == nil {
+		t.Error("invalid range was read")
+	}
+}
+
+func sameRange(a, b Range) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for h, w := range a {
+		if b[h] != w {
+			return false
+		}
+	}
+	return true
+}
+
+func mustRange(t *testing.T, s string) Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func mustCards(t *testing.T, s string) []Card {
+	t.Helper()
+	cards, err := ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// Rate is the value of one unit of Currency in $ on Date.
+	Rate struct {
+		Date     time.Time `json:"date"`
+		Currency Currency  `json:"currency"`
+		USD      float64   `json:"usd"`
+	}
+
+	// Rates looks up historical exchange rates.
+	Rates struct {
+		byCurrency map[Currency][]Rate
+	}
+)
+
+func NewRates(rates []Rate) Rates {
+	r := Rates{byCurrency: make(map[Currency][]Rate)}
+	for _, rate := range rates {
+		r.byCurrency[rate.Currency] = append(r.byCurrency[rate.Currency], rate)
+	}
+	for _, list := range r.byCurrency {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Date.Before(list[j].Date)
+		})
+	}
+	return r
+}
+
+// USD returns the value of one unit of c in $ on the given date: the latest
+// known rate not after it, the earliest one before any is known, and the
+// fixed summary rate when there are none.
+func (r Rates) USD(c Currency, date time.Time) float64 {
+	if c == USD {
+		return 1
+	}
+	list := r.byCurrency[c]
+	if len(list) == 0 {
+		return float64(c.ToDollar(1))
+	}
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].Date.After(date)
+	})
+	if i == 0 {
+		return list[0].USD
+	}
+	return list[i-1].USD
+}
+
+// Convert converts an amount between currencies at the rates of the given date.
+func (r Rates) Convert(amount float64, from, to Currency, date time.Time) float64 {
+	if from == to {
+		return amount
+	}
+	return amount * r.USD(from, date) / r.USD(to, date)
+}
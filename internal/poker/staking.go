@@ -0,0 +1,92 @@
+package poker
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type (
+	// Deal is a staking deal of a player with a backer. The backer buys Sold
+	// percent of the action at Markup times its cost and gets that part of
+	// the prizes. Of the backer's profit the player keeps PlayerCut percent,
+	// paid at the settlements once the makeup, the backer's unrecovered
+	// losses, is cleared.
+	Deal struct {
+		ID        int64      `json:"id"`
+		PlayerID  int64      `json:"player_id"`
+		Backer    string     `json:"backer"`
+		Sold      float64    `json:"sold"`
+		Markup    float64    `json:"markup"`
+		PlayerCut float64    `json:"player_cut"`
+		Makeup    MakeupRule `json:"makeup"`
+		Period    Period     `json:"period"`
+		// Start and End bound the tournaments of the deal, End is zero for open deals.
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end,omitempty"`
+		Note  string    `json:"note,omitempty"`
+	}
+
+	// MakeupRule tells what happens to the makeup at a settlement.
+	MakeupRule string
+	// Period is how often a deal is settled.
+	Period string
+)
+
+const (
+	// MakeupCarry keeps the makeup until profits clear it.
+	MakeupCarry MakeupRule = "carry"
+	// MakeupReset forgives the makeup at every settlement.
+	MakeupReset MakeupRule = "reset"
+
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	// PeriodDeal settles once, at the end of the deal.
+	PeriodDeal Period = "deal"
+)
+
+func (d Deal) Validate() error {
+	if d.PlayerID <= 0 {
+		return errors.New("deal needs a player")
+	}
+	if strings.TrimSpace(d.Backer) == "" {
+		return errors.New("backer is required")
+	}
+	if d.Sold <= 0 || d.Sold > 100 {
+		return errors.New("sold must be within 0..100 percent")
+	}
+	if d.Markup < 1 {
+		return errors.New("markup cannot be below 1")
+	}
+	if d.PlayerCut < 0 || d.PlayerCut > 100 {
+		return errors.New("player cut must be within 0..100 percent")
+	}
+	switch d.Makeup {
+	case MakeupCarry, MakeupReset:
+	default:
+		return errors.New("makeup must be carry or reset")
+	}
+	switch d.Period {
+	case PeriodWeek, PeriodMonth, PeriodDeal:
+	default:
+		return errors.New("period must be week, month or deal")
+	}
+	if d.Start.IsZero() {
+		return errors.New("deal needs a start")
+	}
+	if !d.End.IsZero() && !d.End.After(d.Start) {
+		return errors.New("deal must end after it starts")
+	}
+	return nil
+}
+
+// Covers tells whether a tournament started at the time falls under the deal.
+func (d Deal) Covers(started time.Time) bool {
+	return !started.Before(d.Start) && (d.End.IsZero() || started.Before(d.End))
+}
+
+// Overlaps tells whether the deals share some time.
+func (d Deal) Overlaps(other Deal) bool {
+	endsAfter := func(a, b Deal) bool { return a.End.IsZero() || a.End.After(b.Start) }
+	return endsAfter(d, other) && endsAfter(other, d)
+}
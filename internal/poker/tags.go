@@ -0,0 +1,56 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Note is a free text comment on a tournament.
+	Note struct {
+		ID           int64     `json:"id"`
+		TournamentID string    `json:"tournament_id"`
+		AccountID    int64     `json:"account_id"`
+		Text         string    `json:"text"`
+		Created      time.Time `json:"created"`
+		Updated      time.Time `json:"updated"`
+	}
+
+	// TagUsage counts the tournaments marked with a tag.
+	TagUsage struct {
+		Tag         string `json:"tag"`
+		Tournaments int    `json:"tournaments"`
+	}
+)
+
+const maxTagLength = 64
+
+// NormalizeTag lowercases a tag and collapses its spaces, so "Played  on Laptop"
+// and "played on laptop" are the same tag.
+func NormalizeTag(s string) (string, error) {
+	tag := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if tag == "" {
+		return "", errors.New("tag is empty")
+	}
+	if len(tag) > maxTagLength {
+		return "", fmt.Errorf("tag is longer than %d bytes", maxTagLength)
+	}
+	if strings.ContainsAny(tag, ",/") {
+		return "", fmt.Errorf("tag %q cannot contain ',' or '/'", tag)
+	}
+	return tag, nil
+}
+
+func (t Tournament) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+func (n Note) Validate() error {
+	if strings.TrimSpace(n.Text) == "" {
+		return errors.New("note text is required")
+	}
+	return nil
+}
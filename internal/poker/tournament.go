@@ -14,15 +14,36 @@ type (
 	Tournament struct {
 		ID             string
 		BI             float32 //in $
+		BIRake         float32 //part of BI
+		BIBounty       float32 //part of BI
 		Players        int
 		TotalPrizePool float32 //without rake
 		Started        time.Time
+		Finished       time.Time // zero when unknown
+		Duration       time.Duration
 		MyPlace        int
 		MyPrize        float32
 		Reentries      int
 		Name           string
 		Type           TournamentType
 		Free           bool
+		Currency       Currency // amounts above are converted to $
+		Room           string
+		// Edited is set once the tournament is corrected by hand, imports leave it alone then.
+		Edited bool
+		// Deleted is zero unless the tournament was deleted by hand.
+		Deleted time.Time
+		// Tags are normalized, see NormalizeTag, and sorted.
+		Tags []string
+		// AccountID is the account the tournament was played on, the same
+		// tournament can be played by several accounts of the team.
+		AccountID int64
+		// PlayerID, Player and Account, the room and screen name, are filled from the account.
+		PlayerID int64
+		Player   string
+		Account  string
+		// Hero is the screen name in the summary, it picks the account on import.
+		Hero string
 	}
 	TournamentType string
 )
@@ -41,15 +62,70 @@ const (
 	Flipout      TournamentType = "Flipout"
 )
 
+var TournamentTypes = []TournamentType{
+	BountyHunter, Classic, Turbo, Hyper, TBuilder, Freeroll,
+	FlipAndGo, Satellite, DeepStacks, Shootout, Flipout,
+}
+
 var (
 	biRegexp         = regexp.MustCompile(`([$¥€])([0-9]+(?:\.[0-9]+)?)`)
 	totalPrizeRegexp = regexp.MustCompile(`([$¥€])([0-9,]+(?:\.[0-9]+)?)`)
 	placeRegexp      = regexp.MustCompile(`(\d+)(?:st|nd|rd|th)? place`)
 	reEntriesRegex   = regexp.MustCompile(`You made (\d+) re-entries`)
 	myPrizeRegex     = regexp.MustCompile(`received a total of [T,C]?([$¥€])([0-9,]+(?:\.[0-9]+)?)`)
+	tableSizeRegexp  = regexp.MustCompile(`\[(\d+)-Max\]`)
 	dateLayout       = "2006/01/02 15:04:05"
 )
 
+const defaultTableSize = 9
+
+// Cost is what was paid to enter the tournament. Tickets and freerolls cost nothing.
+func (t Tournament) Cost() float32 {
+	if t.Free {
+		return 0
+	}
+	return t.BI
+}
+
+// TotalCost is what was paid for every entry, re-entries included.
+func (t Tournament) TotalCost() float32 {
+	return t.Cost() * float32(1+t.Reentries)
+}
+
+// RakePaid is the rake of every entry, re-entries included.
+func (t Tournament) RakePaid() float32 {
+	if t.Free {
+		return 0
+	}
+	return t.BIRake * float32(1+t.Reentries)
+}
+
+func (t Tournament) Profit() float32 {
+	return t.MyPrize - t.Cost()
+}
+
+// SetFinished records the end of the tournament, ignoring times before its start.
+func (t *Tournament) SetFinished(finished time.Time) {
+	if finished.Before(t.Started) {
+		return
+	}
+	t.Finished = finished
+	t.Duration = finished.Sub(t.Started)
+}
+
+// TableSize is the number of seats per table taken from the "[7-Max]" part of the name.
+func (t Tournament) TableSize() int {
+	match := tableSizeRegexp.FindStringSubmatch(t.Name)
+	if match == nil {
+		return defaultTableSize
+	}
+	size, err := strconv.Atoi(match[1])
+	if err != nil || size <= 1 {
+		return defaultTableSize
+	}
+	return size
+}
+
 func ParseTournament(s *bufio.Scanner) (*Tournament, error) {
 	/*
 		Tournament #183300341, Bounty Hunters Special $2.50 [7-Max], Hold'em No Limit
@@ -85,7 +161,10 @@ func ParseTournament(s *bufio.Scanner) (*Tournament, error) {
 			if err != nil {
 				return nil, err
 			}
-			t.BI = bi
+			t.BI = bi.Total()
+			t.BIRake = bi.Rake
+			t.BIBounty = bi.Bounty
+			t.Currency = bi.Currency
 		case 2:
 			players, err := parsePlayersCount(s.Text())
 			if err != nil {
@@ -104,6 +183,8 @@ func ParseTournament(s *bufio.Scanner) (*Tournament, error) {
 				return nil, err
 			}
 			t.Started = startTime
+		case 5:
+			t.Hero = parseHero(s.Text())
 		case 6:
 			place, err := parsePlace(s.Text())
 			if err != nil {
@@ -165,6 +246,18 @@ func parsePrizeAndReentry(s string) (float32, int, bool, error) {
 	return value, reEntries, false, nil
 }
 
+func parseHero(s string) string {
+	//316th : Hero, $1
+	_, rest, ok := strings.Cut(s, " : ")
+	if !ok {
+		return ""
+	}
+	if i := strings.LastIndex(rest, ", "); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimSpace(rest)
+}
+
 func parsePlace(s string) (int, error) {
 	//You finished the tournament in 12th place.
 	match := placeRegexp.FindStringSubmatch(s)
@@ -226,33 +319,35 @@ func parsePlayersCount(s string) (int, error) {
 	return count, nil
 }
 
-func parseBI(s string) (float32, error) {
+func parseBI(s string) (BuyIn, error) {
 	//Buy-in: $1.3+$0.2+$1
 	matches := biRegexp.FindAllStringSubmatch(s, -1)
 
 	if matches == nil {
-		return 0, fmt.Errorf("no valid numbers found in input")
+		return BuyIn{}, fmt.Errorf("no valid numbers found in input")
 	}
 
-	var sum float32
 	var currency string
-
+	parts := make([]float32, 0, len(matches))
 	for _, match := range matches {
 		if currency == "" {
 			currency = match[1]
 		}
 		value, err := strconv.ParseFloat(match[2], 32)
 		if err != nil {
-			return 0, fmt.Errorf("failed to parse number: %v", err)
+			return BuyIn{}, fmt.Errorf("failed to parse number: %v", err)
 		}
-		sum += float32(value)
-	}
-	if currency == "¥" {
-		sum = yuanToDollar(sum)
-	} else if currency == "€" {
-		sum = euroToDollar(sum)
+		amount := float32(value)
+		if currency == "¥" {
+			amount = yuanToDollar(amount)
+		} else if currency == "€" {
+			amount = euroToDollar(amount)
+		}
+		parts = append(parts, amount)
 	}
-	return sum, nil
+	b := NewBuyIn(parts)
+	b.Currency = currencyBySymbol(currency)
+	return b, nil
 }
 
 func parseName(s string) (string, TournamentType, error) {
@@ -266,33 +361,9 @@ func parseName(s string) (string, TournamentType, error) {
 		return "", "", fmt.Errorf("Cannot parse tournament id")
 	}
 
-	var ttype TournamentType
-	if strings.Contains(arr[1], "Bounty") || strings.Contains(arr[1], "Баунти") {
-		ttype = BountyHunter
-	} else if strings.Contains(arr[1], "Daily Big") || strings.Contains(arr[1], "Sunday Big") ||
-		strings.Contains(arr[1], "Daily Special") || strings.Contains(arr[1], "Weekender") {
-		ttype = Classic
-	} else if strings.Contains(arr[1], string(Turbo)) {
-		ttype = Turbo
-	} else if strings.Contains(arr[1], string(Hyper)) {
-		ttype = Hyper
-	} else if strings.Contains(arr[1], string(TBuilder)) {
-		ttype = TBuilder
-	} else if strings.Contains(arr[1], "Chat&Play") || strings.Contains(arr[1], "ThanksHoldemPlayers") {
-		ttype = Freeroll
-	} else if strings.Contains(arr[1], "Шутаут") {
-		ttype = Shootout
-	} else if strings.Contains(arr[1], string(FlipAndGo)) {
-		ttype = FlipAndGo
-	} else if strings.Contains(s, string(Flipout)) {
-		ttype = Flipout
-	} else if strings.Contains(arr[1], string(DeepStacks)) || strings.Contains(arr[1], " Monster Stack") {
-		ttype = DeepStacks
-	} else if strings.Contains(arr[1], string(Satellite)) || strings.Contains(arr[1], string("Step to")) || strings.Contains(arr[1], string("Road to")) {
-		ttype = Satellite
-	} else {
+	ttype, ok := typeByName(arr[1], s)
+	if !ok {
 		fmt.Println("Cannot parse tournament type(set to Classic) : ", arr)
-		ttype = Classic
 	}
 
 	if strings.TrimSpace(arr[len(arr)-1]) != "Hold'em No Limit" {
@@ -301,6 +372,52 @@ func parseName(s string) (string, TournamentType, error) {
 	return id, ttype, nil
 }
 
+// TypeByName guesses the tournament type from its name, reporting whether
+// it was recognised. Unknown names are Classic.
+func TypeByName(name string) (TournamentType, bool) {
+	return typeByName(name, name)
+}
+
+// typeByName looks at the title part of the summary header, a few types are
+// only mentioned elsewhere in the line.
+func typeByName(title, line string) (TournamentType, bool) {
+	if strings.Contains(title, "Bounty") || strings.Contains(title, "Баунти") {
+		return BountyHunter, true
+	}
+	if strings.Contains(title, "Daily Big") || strings.Contains(title, "Sunday Big") ||
+		strings.Contains(title, "Daily Special") || strings.Contains(title, "Weekender") {
+		return Classic, true
+	}
+	if strings.Contains(title, string(Turbo)) {
+		return Turbo, true
+	}
+	if strings.Contains(title, string(Hyper)) {
+		return Hyper, true
+	}
+	if strings.Contains(title, string(TBuilder)) {
+		return TBuilder, true
+	}
+	if strings.Contains(title, "Chat&Play") || strings.Contains(title, "ThanksHoldemPlayers") {
+		return Freeroll, true
+	}
+	if strings.Contains(title, "Шутаут") {
+		return Shootout, true
+	}
+	if strings.Contains(title, string(FlipAndGo)) {
+		return FlipAndGo, true
+	}
+	if strings.Contains(line, string(Flipout)) {
+		return Flipout, true
+	}
+	if strings.Contains(title, string(DeepStacks)) || strings.Contains(title, " Monster Stack") {
+		return DeepStacks, true
+	}
+	if strings.Contains(title, string(Satellite)) || strings.Contains(title, string("Step to")) || strings.Contains(title, string("Road to")) {
+		return Satellite, true
+	}
+	return Classic, false
+}
+
 func euroToDollar(y float32) float32 {
 	return y * 1.04
 }
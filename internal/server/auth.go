@@ -0,0 +1,239 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/auth"
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+const (
+	// sessionCookie keeps the login of the HTML pages.
+	sessionCookie = "poker_session"
+	// sessionMaxAge matches the lifetime of the session on the server.
+	sessionMaxAge = 30 * 24 * time.Hour
+)
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>Sign in</title>
+    <style>
+        body { font-family: sans-serif; display: flex; justify-content: center; margin-top: 10vh; }
+        form { display: flex; flex-direction: column; gap: 12px; width: 260px; }
+        label { display: flex; flex-direction: column; font-size: 13px; }
+        .error { color: #c0392b; font-size: 13px; }
+    </style>
+</head>
+<body>
+<form method="post" action="/login">
+    <input type="hidden" name="next" value="{{ .Next }}">
+    <label>User <input type="text" name="name" value="{{ .Name }}" autofocus required></label>
+    <label>Password <input type="password" name="password" required></label>
+    {{- if .Error }}
+    <div class="error">{{ .Error }}</div>
+    {{- end }}
+    <button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
+
+// guard authenticates a request by its bearer token or session cookie and
+// checks the role of the user: reads need the read access, other methods
+// the change one. Pages opened in a browser are sent to the login page.
+func (s *Server) guard(read, change auth.Access, h func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, err := s.handManager.Authenticate(r.Context(), requestSecret(r))
+		if errors.Is(err, hander.ErrUnauthorized) {
+			if r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			RespondError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		need := change
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			need = read
+		}
+		if !u.Can(need) {
+			RespondError(w, http.StatusForbidden, fmt.Sprintf("%s: role %s", hander.ErrForbidden, u.Role))
+			return
+		}
+		h(w, r.WithContext(auth.WithUser(r.Context(), u)))
+	}
+}
+
+func requestSecret(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if secret, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(secret)
+		}
+		return ""
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// requestUser is the caller, ok is false for requests that did not pass guard.
+func requestUser(r *http.Request) (auth.User, bool) {
+	return auth.UserFrom(r.Context())
+}
+
+// checkVisible hides the tournaments of players the caller does not see,
+// they are reported as not found.
+func (s *Server) checkVisible(r *http.Request, id string, account int64) error {
+	u, ok := requestUser(r)
+	if !ok || u.Role == auth.RoleOwner || len(u.Players) == 0 {
+		return nil
+	}
+	t, err := s.handManager.GetTournament(r.Context(), id, account)
+	if err != nil {
+		return err
+	}
+	if !u.Sees(t.PlayerID) {
+		return fmt.Errorf("%w: tournament #%s", hander.ErrNotFound, id)
+	}
+	return nil
+}
+
+// visiblePlayers drops the players the caller does not see.
+func visiblePlayers(r *http.Request, players []poker.Player) []poker.Player {
+	u, ok := requestUser(r)
+	if !ok {
+		return players
+	}
+	res := make([]poker.Player, 0, len(players))
+	for _, p := range players {
+		if u.Sees(p.ID) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// visibleAccounts drops the accounts of the players the caller does not see.
+func visibleAccounts(r *http.Request, accounts []poker.Account) []poker.Account {
+	u, ok := requestUser(r)
+	if !ok {
+		return accounts
+	}
+	res := make([]poker.Account, 0, len(accounts))
+	for _, a := range accounts {
+		if u.Sees(a.PlayerID) {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+type loginRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// login shows the sign in page and opens a session. A JSON client gets the
+// session secret back to use as a bearer token.
+func (s *Server) login() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.loginPage(w, loginPage{Next: safeNext(r.FormValue("next"))})
+		case http.MethodPost:
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+				var req loginRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+					return
+				}
+				secret, u, err := s.handManager.Login(r.Context(), req.Name, req.Password)
+				if err != nil {
+					RespondManagerError(w, err)
+					return
+				}
+				RespondJSON(w, http.StatusOK, map[string]any{"token": secret, "user": u})
+				return
+			}
+			page := loginPage{Next: safeNext(r.FormValue("next")), Name: r.FormValue("name")}
+			secret, _, err := s.handManager.Login(r.Context(), page.Name, r.FormValue("password"))
+			if errors.Is(err, hander.ErrUnauthorized) {
+				page.Error = "Wrong user name or password."
+				w.WriteHeader(http.StatusUnauthorized)
+				s.loginPage(w, page)
+				return
+			}
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     sessionCookie,
+				Value:    secret,
+				Path:     "/",
+				MaxAge:   int(sessionMaxAge.Seconds()),
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+			http.Redirect(w, r, page.Next, http.StatusSeeOther)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+type loginPage struct {
+	Next, Name, Error string
+}
+
+func (s *Server) loginPage(w http.ResponseWriter, page loginPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := loginTemplate.Execute(w, page); err != nil {
+		ServerError(w)
+	}
+}
+
+// logout ends the session of the cookie or revokes the bearer token.
+func (s *Server) logout() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.handManager.Logout(r.Context(), requestSecret(r)); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// safeNext keeps the redirect after the login on this site.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/dashboard"
+	}
+	return next
+}
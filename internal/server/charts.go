@@ -0,0 +1,201 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// bankrollSubtitle tells when the ledger is left out of the bankroll.
+func bankrollSubtitle(series stats.Series) string {
+	if series.LedgerOmitted {
+		return "Изменение BR по датам, без депозитов, выводов и рейкбека"
+	}
+	return "Изменение BR по датам"
+}
+
+// newLine draws a series, with its moving average and confidence interval when present.
+func newLine(title, subtitle string, series stats.Series) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeInfographic}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    title,
+			Subtitle: subtitle,
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Trigger: "axis"}),
+	)
+
+	xaxis := make([]string, len(series.Points))
+	values := make([]opts.LineData, len(series.Points))
+	var ma, low, high []opts.LineData
+	hasBand := false
+	for i, p := range series.Points {
+		xaxis[i] = p.X
+		values[i] = opts.LineData{Value: formatValue(p.Value)}
+		if p.MA != nil {
+			ma = append(ma, opts.LineData{Value: formatValue(*p.MA)})
+		}
+		low = append(low, optionalLineData(p.Low))
+		high = append(high, optionalLineData(p.High))
+		hasBand = hasBand || p.Low != nil
+	}
+	line.SetXAxis(xaxis).
+		AddSeries("Current "+title, values,
+			charts.WithMarkPointNameTypeItemOpts(
+				opts.MarkPointNameTypeItem{Name: "Точка", Type: "circle"},
+			))
+	if len(ma) > 0 {
+		line.AddSeries("Moving average", ma)
+	}
+	if hasBand {
+		dashed := charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Opacity: 0.6})
+		line.AddSeries("95% low", low, dashed)
+		line.AddSeries("95% high", high, dashed)
+	}
+	line.SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}))
+	return line
+}
+
+// addEVLine draws the all-in adjusted bankroll over the bankroll line, the
+// series share their points.
+func addEVLine(line *charts.Line, ev stats.Series) {
+	if len(ev.Points) == 0 {
+		return
+	}
+	values := make([]opts.LineData, len(ev.Points))
+	for i, p := range ev.Points {
+		values[i] = opts.LineData{Value: formatValue(p.Value)}
+	}
+	line.AddSeries("EV adjusted", values, charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed"}))
+}
+
+// highlightTag pins the points holding tournaments marked with the tag.
+func highlightTag(line *charts.Line, series stats.Series, tag string) {
+	if tag == "" || len(line.MultiSeries) == 0 {
+		return
+	}
+	var items []opts.MarkPointNameCoordItem
+	for _, p := range series.Points {
+		if slices.Contains(p.Tags, tag) {
+			items = append(items, opts.MarkPointNameCoordItem{
+				Name:       tag,
+				Coordinate: []interface{}{p.X, formatValue(p.Value)},
+			})
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+	line.MultiSeries[0].ConfigureSeriesOpts(
+		charts.WithMarkPointNameCoordItemOpts(items...),
+		charts.WithMarkPointStyleOpts(opts.MarkPointStyle{Symbol: []string{"pin"}, SymbolSize: 24}),
+	)
+}
+
+func optionalLineData(v *float64) opts.LineData {
+	if v == nil {
+		return opts.LineData{Value: "-"}
+	}
+	return opts.LineData{Value: formatValue(*v)}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func newBar(title, subtitle string) *charts.Bar {
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeInfographic}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    title,
+			Subtitle: subtitle,
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Trigger: "axis"}),
+	)
+	return bar
+}
+
+func volumeChart(series stats.Series) *charts.Bar {
+	bar := newBar("Volume", "Количество турниров")
+	xaxis := make([]string, len(series.Points))
+	values := make([]opts.BarData, len(series.Points))
+	for i, p := range series.Points {
+		xaxis[i] = p.X
+		values[i] = opts.BarData{Value: p.Tournaments}
+	}
+	bar.SetXAxis(xaxis).AddSeries("Tournaments", values)
+	return bar
+}
+
+func finishChart(bins []stats.Bin) *charts.Bar {
+	bar := newBar("Finishes", "Распределение мест, % от поля")
+	xaxis := make([]string, len(bins))
+	values := make([]opts.BarData, len(bins))
+	for i, b := range bins {
+		xaxis[i] = b.Label
+		values[i] = opts.BarData{Value: b.Count}
+	}
+	bar.SetXAxis(xaxis).AddSeries("Tournaments", values)
+	return bar
+}
+
+func profitByTypeChart(groups []stats.Group) *charts.Bar {
+	bar := newBar("Profit by type", "Профит по типам турниров")
+	xaxis := make([]string, len(groups))
+	values := make([]opts.BarData, len(groups))
+	for i, g := range groups {
+		xaxis[i] = g.Key
+		values[i] = opts.BarData{Value: formatValue(g.Profit)}
+	}
+	bar.SetXAxis(xaxis).AddSeries("Profit", values)
+	return bar
+}
+
+// newStakingLine plots the cumulative shares by tournament, the player's
+// cut is added on the tournament closing its settlement period.
+func newStakingLine(report stats.StakingReport) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeInfographic}),
+		charts.WithTitleOpts(opts.Title{
+			Title: "Staking",
+			Subtitle: fmt.Sprintf("%s: продано %.4g%% с наценкой %.4g, makeup %.2f",
+				report.Deal.Backer, report.Deal.Sold, report.Deal.Markup, report.Makeup),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Trigger: "axis"}),
+	)
+	xaxis := make([]string, len(report.Rows))
+	player := make([]opts.LineData, len(report.Rows))
+	backer := make([]opts.LineData, len(report.Rows))
+	makeup := make([]opts.LineData, len(report.Rows))
+	var playerTotal, backerTotal float64
+	i := 0
+	for _, st := range report.Settlements {
+		for n := 0; n < st.Tournaments; n++ {
+			row := report.Rows[i]
+			playerTotal += row.PlayerShare
+			backerTotal += row.BackerPrize - row.BackerPaid
+			if n == st.Tournaments-1 {
+				playerTotal += st.PlayerCut
+				backerTotal -= st.PlayerCut
+			}
+			xaxis[i] = strconv.Itoa(i + 1)
+			player[i] = opts.LineData{Value: formatValue(playerTotal)}
+			backer[i] = opts.LineData{Value: formatValue(backerTotal)}
+			makeup[i] = opts.LineData{Value: formatValue(row.Makeup)}
+			i++
+		}
+	}
+	line.SetXAxis(xaxis).
+		AddSeries("Player", player).
+		AddSeries("Backer", backer).
+		AddSeries("Makeup", makeup, charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Opacity: 0.6}))
+	return line
+}
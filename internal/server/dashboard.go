@@ -0,0 +1,190 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+	"github.com/go-echarts/go-echarts/v2/render"
+)
+
+const assetsHost = "https://go-echarts.github.io/go-echarts-assets/assets/"
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>Poker dashboard</title>
+    <script src="{{ .AssetsHost }}echarts.min.js"></script>
+    <script src="{{ .AssetsHost }}themes/infographic.js"></script>
+    <style>
+        body { font-family: sans-serif; }
+        form { display: flex; flex-wrap: wrap; gap: 12px; align-items: flex-end; margin: 12px; }
+        label { display: flex; flex-direction: column; font-size: 13px; }
+        .container { display: flex; justify-content: center; }
+    </style>
+</head>
+<body>
+<form method="get">
+    <label>From <input type="date" name="from" value="{{ .Query.Get "from" }}"></label>
+    <label>To <input type="date" name="to" value="{{ .Query.Get "to" }}"></label>
+    <label>Type
+        <select name="type" multiple size="4">
+        {{- range .Types }}
+            <option value="{{ .Value }}"{{ if .Selected }} selected{{ end }}>{{ .Value }}</option>
+        {{- end }}
+        </select>
+    </label>
+    <label>Player
+        <select name="player">
+            <option value="">Team</option>
+        {{- range .Players }}
+            <option value="{{ .ID }}"{{ if .Selected }} selected{{ end }}>{{ .Name }}</option>
+        {{- end }}
+        </select>
+    </label>
+    <label>Min BI <input type="number" step="0.01" min="0" name="min_bi" value="{{ .Query.Get "min_bi" }}"></label>
+    <label>Max BI <input type="number" step="0.01" min="0" name="max_bi" value="{{ .Query.Get "max_bi" }}"></label>
+    <label>Tags <input type="text" name="tag" value="{{ .Query.Get "tag" }}"></label>
+    <label>Without tags <input type="text" name="exclude_tag" value="{{ .Query.Get "exclude_tag" }}"></label>
+    <label>Highlight tag <input type="text" name="highlight" list="tags" value="{{ .Query.Get "highlight" }}"></label>
+    <datalist id="tags">
+    {{- range .Tags }}
+        <option value="{{ .Tag }}">
+    {{- end }}
+    </datalist>
+    <button type="submit">Apply</button>
+    <a href="?">Reset</a>
+</form>
+<form method="post" action="/logout">
+    <button type="submit">Sign out</button>
+</form>
+{{ range .Charts }}
+{{ .Element }}
+{{ .Script }}
+{{ end }}
+</body>
+</html>
+`))
+
+type (
+	dashboardType struct {
+		Value    poker.TournamentType
+		Selected bool
+	}
+	dashboardPlayer struct {
+		poker.Player
+		Selected bool
+	}
+	dashboardChart struct {
+		Element template.HTML
+		Script  template.HTML
+	}
+)
+
+func (s *Server) dashboard() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		o, err := parseSeriesOptions(r, stats.AxisDate)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		highlight, err := parseHighlight(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tournaments, err := s.handManager.ListTournaments(r.Context(), f)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		// The tags and players to pick from are all those the caller sees.
+		var seen hander.Filter
+		if u, ok := requestUser(r); ok {
+			seen.Players, _ = u.Scope(nil)
+		}
+		tags, err := s.handManager.ListTags(r.Context(), seen)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		players, err := s.handManager.ListPlayers(r.Context())
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		bankroll, err := s.handManager.BankrollSeries(r.Context(), f, o)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		roi := stats.ROI(tournaments, stats.SeriesOptions{XAxis: stats.AxisIndex, MAWindow: o.MAWindow})
+		bankrollLine := newLine("BR", bankrollSubtitle(bankroll), bankroll)
+		roiLine := newLine("ROI", "Изменение ROI от количества турниров", roi)
+		highlightTag(bankrollLine, bankroll, highlight)
+		highlightTag(roiLine, roi, highlight)
+		renderers := []render.Renderer{
+			bankrollLine,
+			roiLine,
+			volumeChart(stats.Volume(tournaments, o)),
+			finishChart(stats.FinishHistogram(tournaments, 10)),
+			profitByTypeChart(stats.GroupBy(tournaments, stats.ByType)),
+		}
+		data := struct {
+			AssetsHost string
+			Query      url.Values
+			Types      []dashboardType
+			Players    []dashboardPlayer
+			Tags       []poker.TagUsage
+			Charts     []dashboardChart
+		}{
+			AssetsHost: assetsHost,
+			Query:      r.URL.Query(),
+			Types:      dashboardTypes(f),
+			Players:    dashboardPlayers(visiblePlayers(r, players), f),
+			Tags:       tags,
+		}
+		for _, c := range renderers {
+			snippet := c.RenderSnippet()
+			data.Charts = append(data.Charts, dashboardChart{
+				Element: template.HTML(snippet.Element),
+				Script:  template.HTML(snippet.Script),
+			})
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, data); err != nil {
+			ServerError(w)
+		}
+	}
+}
+
+func dashboardPlayers(players []poker.Player, f hander.Filter) []dashboardPlayer {
+	res := make([]dashboardPlayer, 0, len(players))
+	for _, p := range players {
+		res = append(res, dashboardPlayer{Player: p, Selected: slices.Contains(f.Players, p.ID)})
+	}
+	return res
+}
+
+func dashboardTypes(f hander.Filter) []dashboardType {
+	res := make([]dashboardType, 0, len(poker.TournamentTypes))
+	for _, t := range poker.TournamentTypes {
+		res = append(res, dashboardType{Value: t, Selected: slices.Contains(f.Types, t)})
+	}
+	return res
+}
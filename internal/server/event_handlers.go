@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type (
+	mergeRequest struct {
+		Into string   `json:"into"`
+		Keys []string `json:"keys"`
+	}
+	splitRequest struct {
+		Match string `json:"match"`
+		Key   string `json:"key"`
+	}
+)
+
+// seriesEvents reports volume, ROI, ITM and average field per recurring
+// event of the filtered tournaments.
+func (s *Server) seriesEvents() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		events, err := s.handManager.SeriesEvents(r.Context(), f)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, events)
+	}
+}
+
+func (s *Server) eventRulesHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		rules, err := s.handManager.ListEventRules(r.Context())
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, rules)
+	}
+}
+
+// eventRuleHandler deletes a merge or a split.
+func (s *Server) eventRuleHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid rule id")
+			return
+		}
+		if err := s.handManager.DeleteEventRule(r.Context(), id); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// mergeEvents counts several series as one, e.g. a renamed event.
+func (s *Server) mergeEvents() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req mergeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		rules, err := s.handManager.MergeEvents(r.Context(), req.Into, req.Keys...)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, rules)
+	}
+}
+
+// splitEvent moves the tournaments with the match in their title to a
+// series of their own, e.g. the specials of a daily event.
+func (s *Server) splitEvent() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req splitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		rule, err := s.handManager.SplitEvent(r.Context(), req.Match, req.Key)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, rule)
+	}
+}
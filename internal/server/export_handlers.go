@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/VOVAN1993/poker_hand/internal/export"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+// exportHandler streams tournaments, stats groups or series points as
+// csv, ndjson or json. It takes the usual filters plus format, columns,
+// group and the series options.
+func (s *Server) exportHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		req := export.Request{
+			What:    r.PathValue("what"),
+			Columns: q.Get("columns"),
+			Group:   q.Get("group"),
+			Series:  r.PathValue("series"),
+		}
+		if req.Series != "" {
+			req.What = export.WhatSeries
+		}
+		var err error
+		if req.Format, err = export.ParseFormat(q.Get("format")); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.SeriesOptions, err = parseSeriesOptions(r, stats.AxisDate); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := req.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", req.Format.ContentType())
+		if req.Format == export.CSV {
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", req.What))
+		}
+		if err := export.Run(r.Context(), s.handManager, f, req, w); err != nil {
+			// The status is already sent, the broken body is all the client gets.
+			fmt.Println("export failed:", err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseFilter reads the common tournament filters from the query:
+// from, to (inclusive, 2006-01-02), type, tag and exclude_tag (repeatable or
+// comma separated), min_bi, max_bi.
+func parseFilter(r *http.Request) (hander.Filter, error) {
+	var f hander.Filter
+	q := r.URL.Query()
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid from: %w", err)
+		}
+		f.From = from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid to: %w", err)
+		}
+		f.To = to.AddDate(0, 0, 1)
+	}
+	for _, v := range q["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.Types = append(f.Types, poker.TournamentType(t))
+			}
+		}
+	}
+	var err error
+	if f.Tags, err = parseTags(q["tag"]); err != nil {
+		return f, err
+	}
+	if f.NotTags, err = parseTags(q["exclude_tag"]); err != nil {
+		return f, err
+	}
+	if f.MinBI, err = parseFloatParam(q.Get("min_bi")); err != nil {
+		return f, fmt.Errorf("invalid min_bi: %w", err)
+	}
+	if f.MaxBI, err = parseFloatParam(q.Get("max_bi")); err != nil {
+		return f, fmt.Errorf("invalid max_bi: %w", err)
+	}
+	if f.Players, err = parseIDs(q["player"]); err != nil {
+		return f, fmt.Errorf("invalid player: %w", err)
+	}
+	if f.Accounts, err = parseIDs(q["account"]); err != nil {
+		return f, fmt.Errorf("invalid account: %w", err)
+	}
+	if u, ok := requestUser(r); ok {
+		if f.Players, ok = u.Scope(f.Players); !ok {
+			return f, fmt.Errorf("%s cannot see the players", u.Name)
+		}
+	}
+	return f, nil
+}
+
+// parseSeriesOptions reads x (date|index), bucket (day|week|month) and ma from the query.
+func parseSeriesOptions(r *http.Request, defaultAxis stats.XAxis) (stats.SeriesOptions, error) {
+	var o stats.SeriesOptions
+	q := r.URL.Query()
+	x := q.Get("x")
+	if x == "" {
+		x = string(defaultAxis)
+	}
+	var err error
+	if o.XAxis, err = stats.ParseXAxis(x); err != nil {
+		return o, err
+	}
+	if o.Bucket, err = stats.ParseBucket(q.Get("bucket")); err != nil {
+		return o, err
+	}
+	if o.MAWindow, err = parseIntParam(q.Get("ma")); err != nil {
+		return o, fmt.Errorf("invalid ma: %w", err)
+	}
+	return o, nil
+}
+
+func parseTags(values []string) ([]string, error) {
+	var tags []string
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			if strings.TrimSpace(t) == "" {
+				continue
+			}
+			tag, err := poker.NormalizeTag(t)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func parseIDs(values []string) ([]int64, error) {
+	var ids []int64
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// parseAccount reads the account of a tournament played by several of them.
+func parseAccount(r *http.Request) (int64, error) {
+	v := r.URL.Query().Get("account")
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid account: %w", err)
+	}
+	return id, nil
+}
+
+// parseHighlight reads the tag whose points the charts should mark.
+func parseHighlight(r *http.Request) (string, error) {
+	v := r.URL.Query().Get("highlight")
+	if v == "" {
+		return "", nil
+	}
+	return poker.NormalizeTag(v)
+}
+
+func parseFloatParam(s string) (float32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 32)
+	return float32(v), err
+}
+
+func parseIntParam(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,51 @@
+package server
+
+import "net/http"
+
+// tournamentHands lists the hands of a tournament from the hand histories.
+func (s *Server) tournamentHands() func(w http.ResponseWriter, r *http.Request) {
+	return s.tournamentView(func(r *http.Request, id string, account int64) (any, error) {
+		return s.handManager.TournamentHands(r.Context(), id, account)
+	})
+}
+
+// finalTableICM values the final table all-ins of a tournament for the hero.
+func (s *Server) finalTableICM() func(w http.ResponseWriter, r *http.Request) {
+	return s.tournamentView(func(r *http.Request, id string, account int64) (any, error) {
+		return s.handManager.FinalTableICM(r.Context(), id, account)
+	})
+}
+
+// tournamentEV compares the all-ins of the hero in a tournament with their
+// equity.
+func (s *Server) tournamentEV() func(w http.ResponseWriter, r *http.Request) {
+	return s.tournamentView(func(r *http.Request, id string, account int64) (any, error) {
+		return s.handManager.TournamentEV(r.Context(), id, account)
+	})
+}
+
+// tournamentView answers a GET with what view reads about the tournament
+// of the path, for tournaments the caller sees.
+func (s *Server) tournamentView(view func(r *http.Request, id string, account int64) (any, error)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		res, err := view(r, r.PathValue("id"), account)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, res)
+	}
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
 )
 
 func ServerError(w http.ResponseWriter) {
@@ -27,3 +30,21 @@ func RespondJSON(w http.ResponseWriter, status int, data interface{}) error {
 
 	return nil
 }
+
+// RespondManagerError picks the status from the kind of a hand manager error.
+func RespondManagerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, hander.ErrNotFound):
+		RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, hander.ErrExists):
+		RespondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, hander.ErrInvalid):
+		RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, hander.ErrUnauthorized):
+		RespondError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, hander.ErrForbidden):
+		RespondError(w, http.StatusForbidden, err.Error())
+	default:
+		RespondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
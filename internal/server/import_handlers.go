@@ -0,0 +1,86 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/tracker"
+)
+
+// maxImportSize bounds uploaded tracker exports.
+const maxImportSize = 64 << 20
+
+// importCSV takes a tracker export as the request body or as the "file" of
+// a multipart form. Query params: profile, room, account, dry_run and map=field=Header,
+// repeatable, to override columns of the profile.
+func (s *Server) importCSV() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		p, err := tracker.GetProfile(q.Get("profile"))
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, m := range q["map"] {
+			if err := p.Map(m); err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if room := q.Get("room"); room != "" {
+			p.Room = room
+		}
+		var o hander.CSVImport
+		if o.Account, err = parseAccount(r); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if v := q.Get("dry_run"); v != "" {
+			if o.DryRun, err = strconv.ParseBool(v); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid dry_run: %s", err))
+				return
+			}
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+		var body io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid file: %s", err))
+				return
+			}
+			defer file.Close()
+			body = file
+		}
+
+		report, err := s.handManager.ImportCSV(r.Context(), body, p, o)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
+
+func (s *Server) importProfiles() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		profiles := make([]tracker.Profile, 0, len(tracker.Profiles))
+		for _, name := range tracker.ProfileNames() {
+			profiles = append(profiles, tracker.Profiles[name])
+		}
+		RespondJSON(w, http.StatusOK, profiles)
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type transactionRequest struct {
+	Type     poker.TransactionType `json:"type"`
+	Amount   float32               `json:"amount"`
+	Currency poker.Currency        `json:"currency"`
+	// Date is either 2006-01-02 or RFC 3339.
+	Date string `json:"date"`
+	Room string `json:"room"`
+	Note string `json:"note"`
+	// PlayerID owns the entry, the first player when zero.
+	PlayerID int64 `json:"player_id"`
+}
+
+func (s *Server) balance() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		b, err := s.handManager.Balance(r.Context(), f)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, b)
+	}
+}
+
+func (s *Server) transactionsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			f, err := parseFilter(r)
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			ts, err := s.handManager.ListTransactions(r.Context(), f)
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			RespondJSON(w, http.StatusOK, ts)
+		case http.MethodPost:
+			var req transactionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			t, err := req.transaction()
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			t, err = s.handManager.AddTransaction(r.Context(), t)
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			RespondJSON(w, http.StatusCreated, t)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) transactionHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid transaction id")
+			return
+		}
+		if err := s.handManager.DeleteTransaction(r.Context(), id); err != nil {
+			RespondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (req transactionRequest) transaction() (poker.Transaction, error) {
+	date, err := parseDateTime(req.Date)
+	if err != nil {
+		return poker.Transaction{}, fmt.Errorf("invalid date: %w", err)
+	}
+	return poker.Transaction{
+		Type:     req.Type,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Date:     date,
+		Room:     req.Room,
+		Note:     req.Note,
+		PlayerID: req.PlayerID,
+	}, nil
+}
+
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// payouts lists the paid places and min-cash of the filtered tournaments.
+func (s *Server) payouts() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		payouts, err := s.handManager.Payouts(r.Context(), f)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, payouts)
+	}
+}
+
+func (s *Server) payoutStructuresHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			structures, err := s.handManager.ListPayoutStructures(r.Context())
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, structures)
+		case http.MethodPost:
+			var ps poker.PayoutStructure
+			if err := json.NewDecoder(r.Body).Decode(&ps); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			ps, err := s.handManager.AddPayoutStructure(r.Context(), ps)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, ps)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) payoutStructureHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid structure id")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var ps poker.PayoutStructure
+			if err := json.NewDecoder(r.Body).Decode(&ps); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			ps.ID = id
+			ps, err := s.handManager.UpdatePayoutStructure(r.Context(), ps)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, ps)
+		case http.MethodDelete:
+			if err := s.handManager.DeletePayoutStructure(r.Context(), id); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// tournamentPayout is the payout table of a tournament, stored or estimated.
+func (s *Server) tournamentPayout() func(w http.ResponseWriter, r *http.Request) {
+	return s.tournamentView(func(r *http.Request, id string, account int64) (any, error) {
+		return s.handManager.TournamentPayout(r.Context(), id, account)
+	})
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func (s *Server) playersHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			players, err := s.handManager.ListPlayers(r.Context())
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, visiblePlayers(r, players))
+		case http.MethodPost:
+			var p poker.Player
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			p.ID = 0
+			p, err := s.handManager.AddPlayer(r.Context(), p)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, p)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// playerHandler renames a player.
+func (s *Server) playerHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid player id")
+			return
+		}
+		var p poker.Player
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		p.ID = id
+		p, err = s.handManager.UpdatePlayer(r.Context(), p)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, p)
+	}
+}
+
+func (s *Server) accountsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			accounts, err := s.handManager.ListAccounts(r.Context())
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, visibleAccounts(r, accounts))
+		case http.MethodPost:
+			var a poker.Account
+			if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			a.ID = 0
+			a, err := s.handManager.AddAccount(r.Context(), a)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, a)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// accountHandler moves an account to another player or changes its source dir.
+func (s *Server) accountHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid account id")
+			return
+		}
+		var a poker.Account
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		a.ID = id
+		a, err = s.handManager.UpdateAccount(r.Context(), a)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, a)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+func (s *Server) roi() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		vo, ok := parseVarianceOptions(w, r)
+		if !ok {
+			return
+		}
+		highlight, err := parseHighlight(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		so, err := parseSeriesOptions(r, stats.AxisIndex)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		series := stats.ROI(tournaments, so)
+		if len(series.Points) == 0 {
+			return
+		}
+		// The chart has no use for the bootstrap.
+		vo.Bootstrap = 0
+		variance, err := stats.Variance(r.Context(), tournaments, vo)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		line := newLine("ROI", fmt.Sprintf(
+			"Изменение ROI от количества турниров: %.1f%% (95%%: %.1f..%.1f), для ±%.0f%% нужно %d турниров",
+			variance.ROI, variance.ROILow, variance.ROIHigh, variance.Precision, variance.TournamentsNeeded,
+		), series)
+		highlightTag(line, series, highlight)
+		if series.XAxis == stats.AxisIndex && variance.TournamentsNeeded <= len(series.Points) {
+			line.MultiSeries[0].ConfigureSeriesOpts(charts.WithMarkLineNameXAxisItemOpts(opts.MarkLineNameXAxisItem{
+				Name:  fmt.Sprintf("±%.0f%%", variance.Precision),
+				XAxis: strconv.Itoa(variance.TournamentsNeeded),
+			}))
+		}
+		line.Render(w)
+	}
+}
+
+func (s *Server) plot() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		highlight, err := parseHighlight(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		series, ok := s.series(w, r, s.handManager.BankrollSeries, stats.AxisDate)
+		if !ok || len(series.Points) == 0 {
+			return
+		}
+		ev, ok := s.series(w, r, s.bankrollEV, stats.AxisDate)
+		if !ok {
+			return
+		}
+		line := newLine("BR", bankrollSubtitle(series), series)
+		highlightTag(line, series, highlight)
+		addEVLine(line, ev)
+		line.Render(w)
+	}
+}
+
+// bankrollEV is the EV adjusted bankroll, empty without hand histories.
+func (s *Server) bankrollEV(ctx context.Context, f hander.Filter, o stats.SeriesOptions) (stats.Series, error) {
+	series, err := s.handManager.BankrollEVSeries(ctx, f, o)
+	if errors.Is(err, hander.ErrNoHands) {
+		return stats.Series{}, nil
+	}
+	return series, err
+}
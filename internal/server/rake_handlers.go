@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+func (s *Server) rake() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		RespondJSON(w, http.StatusOK, stats.Rake(tournaments))
+	}
+}
+
+func (s *Server) rakeback() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		months, err := s.handManager.Rakeback(r.Context(), f)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, months)
+	}
+}
+
+func (s *Server) rakebackRulesHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rules, err := s.handManager.ListRakebackRules(r.Context())
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			RespondJSON(w, http.StatusOK, rules)
+		case http.MethodPost:
+			var rule poker.RakebackRule
+			if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			rule, err := s.handManager.AddRakebackRule(r.Context(), rule)
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			RespondJSON(w, http.StatusCreated, rule)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) rakebackRuleHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid rule id")
+			return
+		}
+		if err := s.handManager.DeleteRakebackRule(r.Context(), id); err != nil {
+			RespondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
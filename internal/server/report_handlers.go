@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"money": func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) },
+	"date":  func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>Statement {{ .Year }}</title>
+    <style>
+        body { font-family: sans-serif; font-size: 12px; }
+        table { border-collapse: collapse; width: 100%; margin-bottom: 24px; }
+        th, td { border: 1px solid #999; padding: 2px 6px; }
+        td.num { text-align: right; }
+        @media print { tr { page-break-inside: avoid; } }
+    </style>
+</head>
+<body>
+<h1>Profit statement {{ .Year }}, {{ .Currency }}</h1>
+<p>Wins {{ money .Wins }}, losses {{ money .Losses }}, net {{ money .Net }} {{ .Currency }}</p>
+<h2>Totals</h2>
+<table>
+    <tr><th>Room</th><th>Currency</th><th>Tournaments</th><th>Wins</th><th>Losses</th><th>Net</th><th>Net, {{ .Currency }}</th></tr>
+    {{- range .Totals }}
+    <tr><td>{{ .Room }}</td><td>{{ .Currency }}</td><td class="num">{{ .Tournaments }}</td>
+        <td class="num">{{ money .NativeWins }}</td><td class="num">{{ money .NativeLosses }}</td>
+        <td class="num">{{ money .NativeNet }}</td><td class="num">{{ money .Net }}</td></tr>
+    {{- end }}
+</table>
+<h2>Tournaments</h2>
+<table>
+    <tr><th>Date</th><th>ID</th><th>Name</th><th>Room</th><th>Currency</th><th>Cost</th><th>Prize</th><th>Net</th><th>Rate</th><th>Net, {{ .Currency }}</th></tr>
+    {{- range .Lines }}
+    <tr><td>{{ date .Date }}</td><td>{{ .ID }}</td><td>{{ .Name }}</td><td>{{ .Room }}</td><td>{{ .Currency }}</td>
+        <td class="num">{{ money .NativeCost }}</td><td class="num">{{ money .NativePrize }}</td>
+        <td class="num">{{ money .NativeNet }}</td><td class="num">{{ .Rate }}</td><td class="num">{{ money .Net }}</td></tr>
+    {{- end }}
+</table>
+</body>
+</html>
+`))
+
+type rateRequest struct {
+	Date     string         `json:"date"`
+	Currency poker.Currency `json:"currency"`
+	USD      float64        `json:"usd"`
+}
+
+// statement renders the yearly statement for year and currency (USD by
+// default) as json, csv or html depending on format.
+func (s *Server) statement() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		year, err := strconv.Atoi(q.Get("year"))
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid year")
+			return
+		}
+		currency, err := poker.ParseCurrency(q.Get("currency"))
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		st, err := s.handManager.Statement(r.Context(), year, currency)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		switch q.Get("format") {
+		case "", "json":
+			RespondJSON(w, http.StatusOK, st)
+		case "csv":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=statement-%d.csv", year))
+			writeStatementCSV(w, st)
+		case "html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := statementTemplate.Execute(w, st); err != nil {
+				ServerError(w)
+			}
+		default:
+			RespondError(w, http.StatusBadRequest, "unknown format")
+		}
+	}
+}
+
+func writeStatementCSV(w http.ResponseWriter, st stats.Statement) {
+	cw := csv.NewWriter(w)
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	_ = cw.Write([]string{"date", "id", "name", "room", "currency", "cost", "prize", "net", "rate", "net_" + string(st.Currency)})
+	for _, l := range st.Lines {
+		_ = cw.Write([]string{
+			l.Date.Format(time.RFC3339), l.ID, l.Name, l.Room, string(l.Currency),
+			money(l.NativeCost), money(l.NativePrize), money(l.NativeNet),
+			strconv.FormatFloat(l.Rate, 'f', -1, 64), money(l.Net),
+		})
+	}
+	_ = cw.Write(nil)
+	_ = cw.Write([]string{"room", "currency", "tournaments", "wins", "losses", "net", "net_" + string(st.Currency)})
+	for _, t := range st.Totals {
+		_ = cw.Write([]string{
+			t.Room, string(t.Currency), strconv.Itoa(t.Tournaments),
+			money(t.NativeWins), money(t.NativeLosses), money(t.NativeNet), money(t.Net),
+		})
+	}
+	_ = cw.Write([]string{"total", string(st.Currency), "", money(st.Wins), money(st.Losses), money(st.Net), money(st.Net)})
+	cw.Flush()
+}
+
+func (s *Server) ratesHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rates, err := s.handManager.ListRates(r.Context())
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			RespondJSON(w, http.StatusOK, rates)
+		case http.MethodPost:
+			var req []rateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			rates := make([]poker.Rate, 0, len(req))
+			for _, rr := range req {
+				date, err := time.Parse(dateLayout, rr.Date)
+				if err != nil {
+					RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid date: %s", err))
+					return
+				}
+				rates = append(rates, poker.Rate{Date: date, Currency: rr.Currency, USD: rr.USD})
+			}
+			if err := s.handManager.SaveRates(r.Context(), rates); err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+type seriesFunc func(ctx context.Context, f hander.Filter, o stats.SeriesOptions) (stats.Series, error)
+
+func (s *Server) seriesHandler(build seriesFunc) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		series, ok := s.series(w, r, build, stats.AxisDate)
+		if !ok {
+			return
+		}
+		RespondJSON(w, http.StatusOK, series)
+	}
+}
+
+// tournamentSeries builds a series from the filtered tournaments alone.
+func (s *Server) tournamentSeries(build func([]poker.Tournament, stats.SeriesOptions) stats.Series) seriesFunc {
+	return func(ctx context.Context, f hander.Filter, o stats.SeriesOptions) (stats.Series, error) {
+		tournaments, err := s.handManager.ListTournaments(ctx, f)
+		if err != nil {
+			return stats.Series{}, err
+		}
+		return build(tournaments, o), nil
+	}
+}
+
+// series builds a series for the filters and options given in the request query.
+// On failure the error is already written to w.
+func (s *Server) series(w http.ResponseWriter, r *http.Request, build seriesFunc, defaultAxis stats.XAxis) (stats.Series, bool) {
+	f, err := parseFilter(r)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return stats.Series{}, false
+	}
+	o, err := parseSeriesOptions(r, defaultAxis)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return stats.Series{}, false
+	}
+	series, err := build(r.Context(), f, o)
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return stats.Series{}, false
+	}
+	return series, true
+}
+
+// filteredTournaments lists the tournaments matching the filters in the request query.
+// On failure the error is already written to w.
+func (s *Server) filteredTournaments(w http.ResponseWriter, r *http.Request) ([]poker.Tournament, bool) {
+	f, err := parseFilter(r)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	tournaments, err := s.handManager.ListTournaments(r.Context(), f)
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return tournaments, true
+}
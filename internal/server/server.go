@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/VOVAN1993/poker_hand/internal/auth"
 	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
 )
 
+type handlerFunc = func(w http.ResponseWriter, r *http.Request)
+
 type Server struct {
 	handManager hander.HandManager
 }
@@ -20,12 +24,83 @@ func helloHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) Start() {
+	// Every signed in user reads, changes need the role making them. Owners
+	// manage the team: users, players, imports and reports on everybody.
+	ownerChanges := func(h handlerFunc) handlerFunc { return s.guard(auth.Read, auth.Write, h) }
+	coachChanges := func(h handlerFunc) handlerFunc { return s.guard(auth.Read, auth.Annotate, h) }
+	ownerOnly := func(h handlerFunc) handlerFunc { return s.guard(auth.Admin, auth.Admin, h) }
+	anyUser := func(h handlerFunc) handlerFunc { return s.guard(auth.Read, auth.Read, h) }
+
 	http.HandleFunc("/", helloHandler)
-	http.HandleFunc("/plot/total", s.plot())
-	http.HandleFunc("/plot/roi", s.roi())
-	http.HandleFunc("/tournaments", s.tournamentsHandler())
-	http.HandleFunc("/tournaments/{id}", s.tournamentHandler())
-	http.HandleFunc("/tournaments/{id}/free", s.freeTournament())
+	http.HandleFunc("/login", s.login())
+	http.HandleFunc("/logout", s.logout())
+	http.HandleFunc("/me", anyUser(s.me()))
+	http.HandleFunc("/tokens", anyUser(s.tokensHandler()))
+	http.HandleFunc("/tokens/{id}", anyUser(s.tokenHandler()))
+	http.HandleFunc("/users", ownerOnly(s.usersHandler()))
+	http.HandleFunc("/users/{id}", ownerOnly(s.userHandler()))
+	http.HandleFunc("/dashboard", ownerChanges(s.dashboard()))
+	http.HandleFunc("/plot/total", ownerChanges(s.plot()))
+	http.HandleFunc("/plot/roi", ownerChanges(s.roi()))
+	http.HandleFunc("/plot/finishes", ownerChanges(s.plotFinishes()))
+	http.HandleFunc("/plot/sessions", ownerChanges(s.plotSessions()))
+	http.HandleFunc("/sessions", ownerChanges(s.sessionsHandler()))
+	http.HandleFunc("/sessions/{id}", ownerChanges(s.sessionHandler()))
+	http.HandleFunc("/stats/groups", ownerChanges(s.groups()))
+	http.HandleFunc("/stats/finishes", ownerChanges(s.finishes()))
+	http.HandleFunc("/stats/variance", ownerChanges(s.variance()))
+	http.HandleFunc("/stats/hourly", ownerChanges(s.hourly()))
+	http.HandleFunc("/stats/rake", ownerChanges(s.rake()))
+	http.HandleFunc("/import/csv", ownerOnly(s.importCSV()))
+	http.HandleFunc("/import/profiles", ownerChanges(s.importProfiles()))
+	http.HandleFunc("/export/{what}", ownerChanges(s.exportHandler()))
+	http.HandleFunc("/export/series/{series}", ownerChanges(s.exportHandler()))
+	http.HandleFunc("/reports/statement", ownerOnly(s.statement()))
+	http.HandleFunc("/rates", ownerChanges(s.ratesHandler()))
+	http.HandleFunc("/rakeback", ownerChanges(s.rakeback()))
+	http.HandleFunc("/rakeback/rules", ownerChanges(s.rakebackRulesHandler()))
+	http.HandleFunc("/rakeback/rules/{id}", ownerChanges(s.rakebackRuleHandler()))
+	http.HandleFunc("/series-events", ownerChanges(s.seriesEvents()))
+	http.HandleFunc("/series-events/rules", ownerChanges(s.eventRulesHandler()))
+	http.HandleFunc("/series-events/rules/{id}", ownerChanges(s.eventRuleHandler()))
+	http.HandleFunc("/series-events/merge", ownerChanges(s.mergeEvents()))
+	http.HandleFunc("/series-events/split", ownerChanges(s.splitEvent()))
+	http.HandleFunc("/payouts", ownerChanges(s.payouts()))
+	http.HandleFunc("/payouts/structures", ownerChanges(s.payoutStructuresHandler()))
+	http.HandleFunc("/payouts/structures/{id}", ownerChanges(s.payoutStructureHandler()))
+	http.HandleFunc("/staking/deals", ownerChanges(s.dealsHandler()))
+	http.HandleFunc("/staking/deals/{id}", ownerChanges(s.dealHandler()))
+	http.HandleFunc("/staking/deals/{id}/report", ownerChanges(s.dealReport()))
+	http.HandleFunc("/plot/staking/{id}", ownerChanges(s.plotDeal()))
+	http.HandleFunc("/tools/simulate", ownerChanges(s.simulate()))
+	http.HandleFunc("/tools/icm", anyUser(s.icmHandler()))
+	http.HandleFunc("/tools/equity", anyUser(s.equityHandler()))
+	http.HandleFunc("/tools/range", anyUser(s.rangeHandler()))
+	http.HandleFunc("/series/bankroll", ownerChanges(s.seriesHandler(s.handManager.BankrollSeries)))
+	http.HandleFunc("/series/bankroll-ev", ownerChanges(s.seriesHandler(s.handManager.BankrollEVSeries)))
+	http.HandleFunc("/series/roi", ownerChanges(s.seriesHandler(s.tournamentSeries(stats.ROI))))
+	http.HandleFunc("/bankroll", ownerChanges(s.balance()))
+	http.HandleFunc("/transactions", ownerChanges(s.transactionsHandler()))
+	http.HandleFunc("/transactions/{id}", ownerChanges(s.transactionHandler()))
+	http.HandleFunc("/players", ownerChanges(s.playersHandler()))
+	http.HandleFunc("/players/{id}", ownerChanges(s.playerHandler()))
+	http.HandleFunc("/accounts", ownerChanges(s.accountsHandler()))
+	http.HandleFunc("/accounts/{id}", ownerChanges(s.accountHandler()))
+	http.HandleFunc("/tournaments", ownerChanges(s.tournamentsHandler()))
+	http.HandleFunc("/tournaments/{id}", ownerChanges(s.tournamentHandler()))
+	http.HandleFunc("/tournaments/{id}/free", ownerChanges(s.freeTournament()))
+	http.HandleFunc("/tournaments/{id}/undelete", ownerChanges(s.undeleteTournament()))
+	http.HandleFunc("/tournaments/{id}/payout", ownerChanges(s.tournamentPayout()))
+	http.HandleFunc("/tournaments/{id}/hands", ownerChanges(s.tournamentHands()))
+	http.HandleFunc("/tournaments/{id}/icm", ownerChanges(s.finalTableICM()))
+	http.HandleFunc("/tournaments/{id}/ev", ownerChanges(s.tournamentEV()))
+	http.HandleFunc("/tournaments/{id}/audit", ownerChanges(s.auditHandler()))
+	http.HandleFunc("/tournaments/{id}/tags", coachChanges(s.tournamentTags()))
+	http.HandleFunc("/tournaments/{id}/tags/{tag}", coachChanges(s.tournamentTag()))
+	http.HandleFunc("/tournaments/{id}/notes", coachChanges(s.tournamentNotes()))
+	http.HandleFunc("/tournaments/{id}/notes/{note}", coachChanges(s.tournamentNote()))
+	http.HandleFunc("/tags", ownerChanges(s.tagsHandler()))
+	http.HandleFunc("/audit", ownerOnly(s.auditHandler()))
 	fmt.Println("Starting server at port 8080")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
 		fmt.Println("Server failed:", err)
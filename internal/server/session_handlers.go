@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+func (s *Server) sessionsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		sessions, ok := s.sessions(w, r)
+		if !ok {
+			return
+		}
+		for i := range sessions {
+			sessions[i].List = nil
+		}
+		RespondJSON(w, http.StatusOK, sessions)
+	}
+}
+
+func (s *Server) sessionHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		sessions, ok := s.sessions(w, r)
+		if !ok {
+			return
+		}
+		id := r.PathValue("id")
+		for _, session := range sessions {
+			if session.ID == id {
+				RespondJSON(w, http.StatusOK, session)
+				return
+			}
+		}
+		RespondError(w, http.StatusNotFound, fmt.Sprintf("not found session #%s", id))
+	}
+}
+
+func (s *Server) plotSessions() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		sessions, ok := s.sessions(w, r)
+		if !ok || len(sessions) == 0 {
+			return
+		}
+		bar := newBar("Sessions", "Профит по сессиям")
+		xaxis := make([]string, len(sessions))
+		profit := make([]opts.BarData, len(sessions))
+		tables := make([]opts.BarData, len(sessions))
+		for i, session := range sessions {
+			xaxis[i] = session.ID
+			profit[i] = opts.BarData{Value: formatValue(session.Profit)}
+			tables[i] = opts.BarData{Value: session.MaxTables}
+		}
+		bar.SetXAxis(xaxis).
+			AddSeries("Profit", profit).
+			AddSeries("Max tables", tables)
+		bar.Render(w)
+	}
+}
+
+// sessions reads gap and duration (Go durations such as 90m) from the query.
+func (s *Server) sessions(w http.ResponseWriter, r *http.Request) ([]stats.Session, bool) {
+	var o stats.SessionOptions
+	var err error
+	q := r.URL.Query()
+	for name, dst := range map[string]*time.Duration{"gap": &o.Gap, "duration": &o.Duration} {
+		if v := q.Get(name); v != "" {
+			if *dst, err = time.ParseDuration(v); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", name, err))
+				return nil, false
+			}
+		}
+	}
+	tournaments, ok := s.filteredTournaments(w, r)
+	if !ok {
+		return nil, false
+	}
+	return stats.Sessions(tournaments, o), true
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/hander"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func (s *Server) dealsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			deals, err := s.handManager.ListDeals(r.Context())
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			u, _ := requestUser(r)
+			visible := make([]poker.Deal, 0, len(deals))
+			for _, d := range deals {
+				if u.Sees(d.PlayerID) {
+					visible = append(visible, d)
+				}
+			}
+			RespondJSON(w, http.StatusOK, visible)
+		case http.MethodPost:
+			var d poker.Deal
+			if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			d, err := s.handManager.AddDeal(r.Context(), d)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, d)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// dealHandler reads, replaces and deletes a staking deal.
+func (s *Server) dealHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := s.visibleDeal(w, r)
+		if !ok {
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			d, err := s.handManager.GetDeal(r.Context(), id)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, d)
+		case http.MethodPut:
+			var d poker.Deal
+			if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			d.ID = id
+			d, err := s.handManager.UpdateDeal(r.Context(), d)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, d)
+		case http.MethodDelete:
+			if err := s.handManager.DeleteDeal(r.Context(), id); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// dealReport splits the results of a deal between the player and the
+// backer, with the running makeup and the settlements.
+func (s *Server) dealReport() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, ok := s.visibleDeal(w, r)
+		if !ok {
+			return
+		}
+		report, err := s.handManager.DealReport(r.Context(), id)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
+
+// plotDeal draws what the player and the backer take home over a deal
+// together with the makeup.
+func (s *Server) plotDeal() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, ok := s.visibleDeal(w, r)
+		if !ok {
+			return
+		}
+		report, err := s.handManager.DealReport(r.Context(), id)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		if len(report.Rows) == 0 {
+			return
+		}
+		newStakingLine(report).Render(w)
+	}
+}
+
+// visibleDeal parses the deal id of the path, deals of players the caller
+// does not see are reported as not found.
+func (s *Server) visibleDeal(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid deal id")
+		return 0, false
+	}
+	if u, ok := requestUser(r); ok {
+		d, err := s.handManager.GetDeal(r.Context(), id)
+		if err != nil {
+			RespondManagerError(w, err)
+			return 0, false
+		}
+		if !u.Sees(d.PlayerID) {
+			RespondManagerError(w, fmt.Errorf("%w: deal #%d", hander.ErrNotFound, id))
+			return 0, false
+		}
+	}
+	return id, true
+}
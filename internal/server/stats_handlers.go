@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+type groupsResponse struct {
+	Total  stats.Group   `json:"total"`
+	Groups []stats.Group `json:"groups"`
+}
+
+// groups aggregates the filtered tournaments by group: type, month, day or tag.
+// A tournament with several tags counts in each of their groups.
+func (s *Server) groups() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		key, err := stats.GroupKey(r.URL.Query().Get("group"))
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		RespondJSON(w, http.StatusOK, groupsResponse{
+			Total:  stats.Total(tournaments),
+			Groups: stats.GroupByKeys(tournaments, key),
+		})
+	}
+}
+
+func (s *Server) finishes() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		report, ok := s.finishReport(w, r)
+		if !ok {
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
+
+func (s *Server) plotFinishes() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		report, ok := s.finishReport(w, r)
+		if !ok || report.Tournaments == 0 {
+			return
+		}
+		bar := finishChart(report.Histogram)
+		bar.Title.Subtitle = fmt.Sprintf("ITM %.1f%%, пузырь %.1f%%, финалки %.1f%%, топ-3 %.1f%%",
+			report.ITMRate, report.BubbleRate, report.FinalTableRate, report.Top3Rate)
+		bar.Render(w)
+	}
+}
+
+// finishReport reads bins and deep (deep run percentile) from the query.
+func (s *Server) finishReport(w http.ResponseWriter, r *http.Request) (stats.FinishReport, bool) {
+	var o stats.FinishOptions
+	var err error
+	q := r.URL.Query()
+	if o.Bins, err = parseIntParam(q.Get("bins")); err != nil {
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid bins: %s", err))
+		return stats.FinishReport{}, false
+	}
+	if v := q.Get("deep"); v != "" {
+		if o.DeepRun, err = strconv.ParseFloat(v, 64); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid deep: %s", err))
+			return stats.FinishReport{}, false
+		}
+	}
+	if o.Payouts, err = s.handManager.PayoutBook(r.Context()); err != nil {
+		RespondManagerError(w, err)
+		return stats.FinishReport{}, false
+	}
+	tournaments, ok := s.filteredTournaments(w, r)
+	if !ok {
+		return stats.FinishReport{}, false
+	}
+	return stats.Finishes(tournaments, o), true
+}
+
+func (s *Server) variance() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		o, ok := parseVarianceOptions(w, r)
+		if !ok {
+			return
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		report, err := stats.Variance(r.Context(), tournaments, o)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
+
+// parseVarianceOptions reads precision, bootstrap and seed from the query.
+func parseVarianceOptions(w http.ResponseWriter, r *http.Request) (stats.VarianceOptions, bool) {
+	var o stats.VarianceOptions
+	var err error
+	q := r.URL.Query()
+	if v := q.Get("precision"); v != "" {
+		if o.Precision, err = strconv.ParseFloat(v, 64); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid precision: %s", err))
+			return o, false
+		}
+	}
+	if o.Bootstrap, err = parseIntParam(q.Get("bootstrap")); err != nil {
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid bootstrap: %s", err))
+		return o, false
+	}
+	if o.Bootstrap < 0 || o.Bootstrap > stats.MaxBootstrap {
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("bootstrap must be between 0 and %d", stats.MaxBootstrap))
+		return o, false
+	}
+	if v := q.Get("seed"); v != "" {
+		if o.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid seed: %s", err))
+			return o, false
+		}
+	}
+	return o, true
+}
+
+// hourly reads the assumed duration of tournaments without a known end from the query.
+func (s *Server) hourly() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var assumed time.Duration
+		if v := r.URL.Query().Get("duration"); v != "" {
+			var err error
+			if assumed, err = time.ParseDuration(v); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid duration: %s", err))
+				return
+			}
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		RespondJSON(w, http.StatusOK, stats.Hourly(tournaments, assumed))
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type noteRequest struct {
+	Text string `json:"text"`
+}
+
+// tagsHandler counts the tags of the filtered tournaments the caller sees.
+func (s *Server) tagsHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		f, err := parseFilter(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tags, err := s.handManager.ListTags(r.Context(), f)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, tags)
+	}
+}
+
+func (s *Server) tournamentTags() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		t, err := s.handManager.GetTournament(r.Context(), r.PathValue("id"), account)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		tags := t.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		RespondJSON(w, http.StatusOK, tags)
+	}
+}
+
+// tournamentTag adds (PUT) or removes (DELETE) one tag.
+func (s *Server) tournamentTag() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, tag := r.PathValue("id"), r.PathValue("tag")
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, id, account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			err = s.handManager.AddTag(r.Context(), id, account, tag)
+		case http.MethodDelete:
+			err = s.handManager.RemoveTag(r.Context(), id, account, tag)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Server) tournamentNotes() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, id, account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			notes, err := s.handManager.ListNotes(r.Context(), id, account)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, notes)
+		case http.MethodPost:
+			var req noteRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			n, err := s.handManager.AddNote(r.Context(), poker.Note{TournamentID: id, AccountID: account, Text: req.Text})
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, n)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) tournamentNote() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		noteID, err := strconv.ParseInt(r.PathValue("note"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid note id")
+			return
+		}
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, id, account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		note := poker.Note{ID: noteID, TournamentID: id, AccountID: account}
+		switch r.Method {
+		case http.MethodPut:
+			var req noteRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			note.Text = req.Text
+			n, err := s.handManager.UpdateNote(r.Context(), note)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, n)
+		case http.MethodDelete:
+			if err := s.handManager.DeleteNote(r.Context(), note); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/poker/equity"
+	"github.com/VOVAN1993/poker_hand/internal/poker/icm"
+	"github.com/VOVAN1993/poker_hand/internal/stats"
+)
+
+// simulate runs the bankroll simulation for the query parameters
+// bankroll, buyin, tournaments, paths, samples and seed over the filtered history.
+func (s *Server) simulate() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		o, err := parseSimulationOptions(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tournaments, ok := s.filteredTournaments(w, r)
+		if !ok {
+			return
+		}
+		sim, err := stats.Simulate(r.Context(), tournaments, o)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, sim)
+	}
+}
+
+func parseSimulationOptions(r *http.Request) (stats.SimulationOptions, error) {
+	var o stats.SimulationOptions
+	var err error
+	q := r.URL.Query()
+	for name, dst := range map[string]*float64{"bankroll": &o.Bankroll, "buyin": &o.BuyIn} {
+		if v := q.Get(name); v != "" {
+			if *dst, err = strconv.ParseFloat(v, 64); err != nil {
+				return o, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	for name, dst := range map[string]*int{"tournaments": &o.Tournaments, "paths": &o.Paths, "samples": &o.SamplePaths} {
+		if *dst, err = parseIntParam(q.Get(name)); err != nil {
+			return o, fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	if v := q.Get("seed"); v != "" {
+		if o.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return o, fmt.Errorf("invalid seed: %w", err)
+		}
+	}
+	return o, o.Validate()
+}
+
+type (
+	icmRequest struct {
+		Stacks []float64 `json:"stacks"`
+		// Prizes are the amounts of the places from the first one down,
+		// without them the payout of the tournament is used.
+		Prizes       []float64 `json:"prizes"`
+		TournamentID string    `json:"tournament_id"`
+		AccountID    int64     `json:"account_id"`
+	}
+	icmResponse struct {
+		Prizes []float64 `json:"prizes"`
+		icm.Result
+	}
+)
+
+// icmHandler values chip stacks in money with the ICM.
+func (s *Server) icmHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req icmRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		if len(req.Prizes) == 0 {
+			if req.TournamentID == "" {
+				RespondError(w, http.StatusBadRequest, "prizes or a tournament_id are required")
+				return
+			}
+			if err := s.checkVisible(r, req.TournamentID, req.AccountID); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			payout, err := s.handManager.TournamentPayout(r.Context(), req.TournamentID, req.AccountID)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			req.Prizes = payout.Prizes
+		}
+		res, err := icm.Equity(req.Stacks, req.Prizes)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, icmResponse{Prizes: req.Prizes, Result: res})
+	}
+}
+
+type equityRequest struct {
+	// Players are the hands or ranges, one per player.
+	Players    []poker.Range `json:"players"`
+	Board      string        `json:"board"`
+	Dead       string        `json:"dead"`
+	Iterations int           `json:"iterations"`
+	Seed       int64         `json:"seed"`
+}
+
+// equityHandler computes the all-in equity of hands or ranges.
+func (s *Server) equityHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req equityRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		o := equity.Options{Iterations: req.Iterations, Seed: req.Seed}
+		var err error
+		if o.Board, err = poker.ParseCards(req.Board); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid board: %s", err))
+			return
+		}
+		if o.Dead, err = poker.ParseCards(req.Dead); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid dead cards: %s", err))
+			return
+		}
+		res, err := equity.Calculate(r.Context(), req.Players, o)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondJSON(w, http.StatusOK, res)
+	}
+}
+
+type (
+	rangeRequest struct {
+		Range poker.Range `json:"range"`
+		// Blockers are cards out of the range, e.g. the board or our hand.
+		Blockers string `json:"blockers"`
+	}
+	rangeResponse struct {
+		Range   poker.Range `json:"range"`
+		Combos  float64     `json:"combos"`
+		Percent float64     `json:"percent"`
+	}
+)
+
+// rangeHandler writes a range in the short notation and counts its combos.
+func (s *Server) rangeHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req rangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+			return
+		}
+		blockers, err := poker.ParseCards(req.Blockers)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid blockers: %s", err))
+			return
+		}
+		rng := req.Range.Without(poker.NewCardSet(blockers...))
+		RespondJSON(w, http.StatusOK, rangeResponse{Range: rng, Combos: rng.Count(), Percent: rng.Percent()})
+	}
+}
@@ -1,29 +1,51 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"sort"
-	"strconv"
 
-	"github.com/go-echarts/go-echarts/v2/charts"
-	"github.com/go-echarts/go-echarts/v2/opts"
-	"github.com/go-echarts/go-echarts/v2/types"
+	"github.com/VOVAN1993/poker_hand/internal/poker"
 )
 
+type tournamentRequest struct {
+	ID        string `json:"id"`
+	AccountID int64  `json:"account_id"`
+	poker.TournamentPatch
+}
+
 func (s *Server) tournamentsHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
+		switch r.Method {
+		case http.MethodGet:
+			f, err := parseFilter(r)
+			if err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			ts, err := s.handManager.ListTournaments(r.Context(), f)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(fmt.Sprintf("Server error: %s", err)))
+				return
+			}
+			RespondJSON(w, http.StatusOK, ts)
+		case http.MethodPost:
+			var req tournamentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			t, _ := req.Apply(poker.Tournament{ID: req.ID, AccountID: req.AccountID})
+			t, err := s.handManager.AddTournament(r.Context(), t)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, t)
+		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		ts, err := s.handManager.ListTournaments(r.Context())
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = w.Write([]byte(fmt.Sprintf("Server error: %s", err)))
-			return
 		}
-		RespondJSON(w, http.StatusOK, ts)
 	}
 }
 
@@ -33,160 +55,114 @@ func (s *Server) freeTournament() func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
-		id := r.PathValue("id")
-		if err := s.handManager.FreeTournament(r.Context(), id); err != nil {
-			RespondError(w, http.StatusNotFound, err.Error())
+		account, err := parseAccount(r)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.checkVisible(r, r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		if err := s.handManager.FreeTournament(r.Context(), r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
 			return
 		}
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+// tournamentHandler reads, corrects (PATCH with the changed fields only) and
+// deletes a tournament. Deleted ones are hidden until undeleted. The account
+// query parameter picks the tournament when several accounts played it.
 func (s *Server) tournamentHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
 		id := r.PathValue("id")
-		t, err := s.handManager.GetTournament(r.Context(), id)
+		account, err := parseAccount(r)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = w.Write([]byte(fmt.Sprintf("Server error: %s", err)))
+			RespondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		RespondJSON(w, http.StatusOK, t)
+		if err := s.checkVisible(r, id, account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			t, err := s.handManager.GetTournament(r.Context(), id, account)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, t)
+		case http.MethodPatch:
+			var p poker.TournamentPatch
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			t, err := s.handManager.UpdateTournament(r.Context(), id, account, p)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, t)
+		case http.MethodDelete:
+			if err := s.handManager.DeleteTournament(r.Context(), id, account); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
 	}
 }
 
-func (s *Server) roi() func(w http.ResponseWriter, r *http.Request) {
+func (s *Server) undeleteTournament() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
+		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
-		tournaments, err := s.handManager.ListTournaments(r.Context())
+		account, err := parseAccount(r)
 		if err != nil {
-			RespondError(w, http.StatusInternalServerError, err.Error())
+			RespondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		if len(tournaments) == 0 {
+		if err := s.checkVisible(r, r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
 			return
 		}
-		sort.Slice(tournaments, func(i, j int) bool {
-			return tournaments[i].Started.Before(tournaments[j].Started)
-		})
-
-		line := charts.NewLine()
-		line.SetGlobalOptions(
-			charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeInfographic}),
-			charts.WithTitleOpts(opts.Title{
-				Title:    "ROI",
-				Subtitle: "Изменение ROI от количества турниров",
-			}),
-			charts.WithYAxisOpts(opts.YAxis{
-				Min: opts.Float(-50),
-				Max: opts.Float(150),
-			}),
-		)
-
-		genValues := func() []opts.LineData {
-			items := make([]opts.LineData, 0)
-			var curBI, curPrize float32
-			for i := 0; i < len(tournaments); i++ {
-				if tournaments[i].BI < 0.2 {
-					continue
-				}
-				if !tournaments[i].Free {
-					curBI += tournaments[i].BI
-				}
-				fmt.Println(tournaments[i].BI, tournaments[i].MyPrize)
-				curPrize += tournaments[i].MyPrize
-
-				pointRoi := 100 * ((curPrize - curBI) / curBI)
-				fmt.Println("roi ", pointRoi)
-				fmt.Println(curPrize, curBI)
-				items = append(items, opts.LineData{Value: strconv.FormatFloat(float64(pointRoi), 'f', 2, 32)})
-			}
-			return items
-		}
-		values := genValues()
-		xaxis := make([]int, len(values))
-		for i := 0; i < len(values); i++ {
-			xaxis[i] = i + 1
-		}
-		line.SetXAxis(xaxis).
-			AddSeries("Current ROI", values).
-			SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}),
-				charts.WithMarkPointNameTypeItemOpts(
-					opts.MarkPointNameTypeItem{Name: "Точка", Type: "circle"},
-				))
-		line.Render(w)
+		if err := s.handManager.UndeleteTournament(r.Context(), r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
 	}
 }
 
-func (s *Server) plot() func(w http.ResponseWriter, r *http.Request) {
+// auditHandler lists the manual changes of one tournament or, without an id, of all.
+func (s *Server) auditHandler() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
-		tournaments, err := s.handManager.ListTournaments(r.Context())
+		account, err := parseAccount(r)
 		if err != nil {
-			RespondError(w, http.StatusInternalServerError, err.Error())
+			RespondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		if len(tournaments) == 0 {
+		if err := s.checkVisible(r, r.PathValue("id"), account); err != nil {
+			RespondManagerError(w, err)
 			return
 		}
-		sort.Slice(tournaments, func(i, j int) bool {
-			return tournaments[i].Started.Before(tournaments[j].Started)
-		})
-
-		cur := tournaments[0]
-		curi := 0
-		arr := make([]float32, 1)
-		dates := make([]string, 0)
-		dates = append(dates, cur.Started.Format("01.02.2006"))
-		for _, t := range tournaments {
-			if !(cur.Started.Year() == t.Started.Year() &&
-				cur.Started.YearDay() == t.Started.YearDay()) {
-				curi += 1
-				cur = t
-				arr = append(arr, 0.0)
-				dates = append(dates, cur.Started.Format("01.02.2006"))
-			}
-			if t.Free {
-				arr[curi] = arr[curi] + t.MyPrize
-			} else {
-				arr[curi] = arr[curi] + t.MyPrize - t.BI
-			}
+		entries, err := s.handManager.TournamentAudit(r.Context(), r.PathValue("id"), account)
+		if err != nil {
+			RespondManagerError(w, err)
+			return
 		}
-		//// Put data into instance
-		genValues := func() []opts.LineData {
-			items := make([]opts.LineData, len(dates))
-			value := float32(0)
-			for i := 0; i < len(dates); i++ {
-				value += arr[i]
-				items[i] = opts.LineData{Value: strconv.FormatFloat(float64(value), 'f', 2, 32)}
-			}
-			return items
-		}
-		line := charts.NewLine()
-		// set some global options like Title/Legend/ToolTip or anything else
-		line.SetGlobalOptions(
-			charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeInfographic}),
-			charts.WithTitleOpts(opts.Title{
-				Title:    "BR",
-				Subtitle: "Изменение BR по датам",
-			}),
-		)
-		line.SetXAxis(dates).
-			AddSeries("Current BR", genValues()).
-			SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}),
-				charts.WithMarkPointNameTypeItemOpts(
-					opts.MarkPointNameTypeItem{Name: "Точка", Type: "circle"},
-				))
-		line.Render(w)
+		RespondJSON(w, http.StatusOK, entries)
 	}
 }
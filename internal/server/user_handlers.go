@@ -0,0 +1,159 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/auth"
+)
+
+type (
+	userRequest struct {
+		auth.User
+		// Password is required for new users, an empty one keeps the old password.
+		Password string `json:"password"`
+	}
+	tokenRequest struct {
+		Name string `json:"name"`
+		// TTL is a Go duration, like 720h, the token never expires without it.
+		TTL string `json:"ttl"`
+	}
+)
+
+// me shows the caller.
+func (s *Server) me() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		u, _ := requestUser(r)
+		RespondJSON(w, http.StatusOK, u)
+	}
+}
+
+func (s *Server) usersHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			users, err := s.handManager.ListUsers(r.Context())
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, users)
+		case http.MethodPost:
+			var req userRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			u, err := s.handManager.AddUser(r.Context(), req.User, req.Password)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, u)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) userHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid user id")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var req userRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			req.ID = id
+			u, err := s.handManager.UpdateUser(r.Context(), req.User, req.Password)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, u)
+		case http.MethodDelete:
+			if err := s.handManager.DeleteUser(r.Context(), id); err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// tokensHandler lists and issues the API tokens of the caller. The secret
+// of a new token is in the response only.
+func (s *Server) tokensHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, _ := requestUser(r)
+		switch r.Method {
+		case http.MethodGet:
+			tokens, err := s.handManager.ListTokens(r.Context(), u.ID)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusOK, tokens)
+		case http.MethodPost:
+			var req tokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+				return
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				var err error
+				if ttl, err = time.ParseDuration(req.TTL); err != nil {
+					RespondError(w, http.StatusBadRequest, fmt.Sprintf("invalid ttl: %s", err))
+					return
+				}
+			}
+			t, secret, err := s.handManager.CreateToken(r.Context(), u.ID, req.Name, ttl)
+			if err != nil {
+				RespondManagerError(w, err)
+				return
+			}
+			RespondJSON(w, http.StatusCreated, struct {
+				auth.Token
+				Secret string `json:"secret"`
+			}{t, secret})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (s *Server) tokenHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid token id")
+			return
+		}
+		u, _ := requestUser(r)
+		if err := s.handManager.DeleteToken(r.Context(), u.ID, id); err != nil {
+			RespondManagerError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
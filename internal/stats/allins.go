@@ -0,0 +1,215 @@
+package stats
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/poker/equity"
+	"github.com/VOVAN1993/poker_hand/internal/poker/icm"
+)
+
+type (
+	// AllInEV compares what an all-in of the hero brought with what it was
+	// worth when the money went in, in chips and in money.
+	AllInEV struct {
+		HandID string    `json:"hand_id"`
+		Played time.Time `json:"played"`
+		// Board holds the cards out when the betting was over.
+		Board   []string `json:"board,omitempty"`
+		Players int      `json:"players"`
+		// Equity is the chance of the hero to win the main pot in percent,
+		// ties split.
+		Equity float64 `json:"equity"`
+		// Stack and ChipEV are the chips of the hero after the hand and the
+		// chips the hero could expect.
+		Stack      float64 `json:"stack"`
+		ChipEV     float64 `json:"chip_ev"`
+		FinalTable bool    `json:"final_table"`
+		// Value and EV are Stack and ChipEV in money, Luck is what the hero
+		// ran above the expectation.
+		Value float64 `json:"value"`
+		EV    float64 `json:"ev"`
+		Luck  float64 `json:"luck"`
+	}
+
+	// TournamentEV is the result of a tournament with the luck of the
+	// all-ins of the hero taken out.
+	TournamentEV struct {
+		TournamentID   string    `json:"tournament_id"`
+		AccountID      int64     `json:"account_id"`
+		Profit         float64   `json:"profit"`
+		Luck           float64   `json:"luck"`
+		AdjustedProfit float64   `json:"adjusted_profit"`
+		AllIns         []AllInEV `json:"all_ins"`
+	}
+)
+
+// preflopSamples are the boards sampled for a preflop all-in, enumerating
+// them all takes too long over a whole history.
+const preflopSamples = 50_000
+
+// TournamentAllIns evaluates the all-ins of the hero settled at showdown.
+// At the final table the stacks are valued with the ICM and the payout of
+// the tournament, the players there being all that are left. Before it the
+// stacks of the field are unknown, so a chip is worth its share of the
+// prize pool: the pool over the chips of all the entries, each starting
+// with the first stack of the hero.
+func TournamentAllIns(ctx context.Context, t poker.Tournament, hands []poker.Hand, payout poker.Payout) (TournamentEV, error) {
+	res := TournamentEV{
+		TournamentID: t.ID,
+		AccountID:    t.AccountID,
+		Profit:       float64(t.Profit()),
+		AllIns:       make([]AllInEV, 0),
+	}
+	var own []poker.Hand
+	for _, h := range hands {
+		if h.TournamentID == t.ID {
+			own = append(own, h)
+		}
+	}
+	sort.SliceStable(own, func(i, j int) bool {
+		return own[i].Played.Before(own[j].Played)
+	})
+	var chipValue float64
+	for _, h := range own {
+		if hero := h.HeroSeat(); hero >= 0 {
+			if start := h.Seats[hero].Stack; start > 0 && t.Players > 0 {
+				chipValue = float64(t.TotalPrizePool) / (float64(t.Players) * start)
+			}
+			break
+		}
+	}
+	finalTable := make(map[string]bool)
+	for _, h := range FinalTableHands(t, own) {
+		finalTable[h.ID] = true
+	}
+
+	for _, h := range own {
+		a, expected, ok, err := evaluateAllIn(ctx, h)
+		if err != nil {
+			return TournamentEV{}, err
+		}
+		if !ok {
+			continue
+		}
+		hero := h.HeroSeat()
+		a.FinalTable = finalTable[h.ID]
+		if a.FinalTable {
+			value, err := icm.Equity(h.StacksAfter(), payout.Prizes)
+			if err != nil {
+				return TournamentEV{}, err
+			}
+			ev, err := icm.Equity(expected, payout.Prizes)
+			if err != nil {
+				return TournamentEV{}, err
+			}
+			a.Value, a.EV = value.Equity[hero], ev.Equity[hero]
+		} else {
+			if chipValue == 0 {
+				continue
+			}
+			a.Value, a.EV = a.Stack*chipValue, a.ChipEV*chipValue
+		}
+		a.Luck = a.Value - a.EV
+		res.Luck += a.Luck
+		res.AllIns = append(res.AllIns, a)
+	}
+	res.AdjustedProfit = res.Profit - res.Luck
+	return res, nil
+}
+
+// evaluateAllIn finds the chips every player could expect from an all-in
+// with the cards of all the players left in it known, splitting the side
+// pots among the players in them. ok is false for the other hands, and for
+// those settled on the river, where nothing was left to luck.
+func evaluateAllIn(ctx context.Context, h poker.Hand) (AllInEV, []float64, bool, error) {
+	hero := h.HeroSeat()
+	if hero < 0 || !h.AllIn() || h.ActionBoard >= 5 || h.ActionBoard > len(h.Board) {
+		return AllInEV{}, nil, false, nil
+	}
+	board, err := poker.ParseCards(strings.Join(h.Board[:h.ActionBoard], ""))
+	if err != nil {
+		return AllInEV{}, nil, false, nil
+	}
+	var live []int
+	cards := make(map[int]poker.HoleCards)
+	for i, s := range h.Seats {
+		if s.Folded || s.Put == 0 {
+			continue
+		}
+		hc, ok := s.HoleCards()
+		if !ok {
+			return AllInEV{}, nil, false, nil
+		}
+		live = append(live, i)
+		cards[i] = hc
+	}
+	if len(live) < 2 || h.Seats[hero].Folded || h.Seats[hero].Put == 0 {
+		return AllInEV{}, nil, false, nil
+	}
+
+	expected := h.StacksAfter()
+	var levels []float64
+	for _, i := range live {
+		expected[i] = h.Seats[i].Stack - h.Seats[i].Put
+		levels = append(levels, h.Seats[i].Put)
+	}
+	sort.Float64s(levels)
+	levels = slices.Compact(levels)
+	a := AllInEV{HandID: h.ID, Played: h.Played, Board: h.Board[:h.ActionBoard], Players: len(live)}
+	// Every level of the bets is a pot, the last one takes what the folded
+	// players put above it.
+	var prev float64
+	for k, level := range levels {
+		last := k == len(levels)-1
+		var pot float64
+		for _, s := range h.Seats {
+			put := s.Put
+			if !last {
+				put = min(put, level)
+			}
+			pot += max(0, put-min(s.Put, prev))
+		}
+		var eligible []int
+		for _, i := range live {
+			if h.Seats[i].Put >= level {
+				eligible = append(eligible, i)
+			}
+		}
+		main := prev == 0
+		prev = level
+		if len(eligible) == 1 {
+			expected[eligible[0]] += pot
+			continue
+		}
+		ranges := make([]poker.Range, len(eligible))
+		for j, i := range eligible {
+			ranges[j] = poker.Range{cards[i]: 1}
+		}
+		o := equity.Options{Board: board, Seed: 1}
+		if len(board) == 0 {
+			o.Iterations = preflopSamples
+		}
+		eq, err := equity.Calculate(ctx, ranges, o)
+		if err != nil {
+			if ctx.Err() != nil {
+				return AllInEV{}, nil, false, ctx.Err()
+			}
+			// Cards repeated in a broken history.
+			return AllInEV{}, nil, false, nil
+		}
+		for j, i := range eligible {
+			expected[i] += pot * eq.Players[j].Equity / 100
+			if main && i == hero {
+				a.Equity = eq.Players[j].Equity
+			}
+		}
+	}
+	a.Stack = h.StacksAfter()[hero]
+	a.ChipEV = expected[hero]
+	return a, expected, true, nil
+}
@@ -0,0 +1,31 @@
+package stats
+
+import "github.com/VOVAN1993/poker_hand/internal/poker"
+
+type (
+	// Balance is the bankroll split by where the money came from, in $.
+	Balance struct {
+		Tournaments float64                           `json:"tournaments"`
+		Ledger      map[poker.TransactionType]float64 `json:"ledger"`
+		Total       float64                           `json:"total"`
+		// LedgerOmitted marks a balance of tournaments picked by more than
+		// their players, the ledger of the players left out.
+		LedgerOmitted bool `json:"ledger_omitted,omitempty"`
+	}
+)
+
+func NewBalance(ts []poker.Tournament, txs []poker.Transaction) Balance {
+	b := Balance{
+		Ledger: make(map[poker.TransactionType]float64),
+	}
+	for _, t := range ts {
+		b.Tournaments += float64(t.Profit())
+	}
+	b.Total = b.Tournaments
+	for _, tx := range txs {
+		amount := float64(tx.SignedDollars())
+		b.Ledger[tx.Type] += amount
+		b.Total += amount
+	}
+	return b
+}
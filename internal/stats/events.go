@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// SeriesEvent is the results in a recurring event, see poker.EventKey.
+type SeriesEvent struct {
+	Group
+	// Name is the title of the latest tournament of the event.
+	Name  string    `json:"name"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// Events groups the tournaments by their recurring event after the rules,
+// the most played events first.
+func Events(ts []poker.Tournament, rules poker.EventRules) []SeriesEvent {
+	keys := make(map[string]string, len(ts))
+	events := make(map[string]*SeriesEvent)
+	for _, t := range sortedByStart(ts) {
+		key, ok := keys[t.Name]
+		if !ok {
+			key = rules.Key(t.Name)
+			keys[t.Name] = key
+		}
+		e := events[key]
+		if e == nil {
+			e = &SeriesEvent{First: t.Started}
+			events[key] = e
+		}
+		e.Name, e.Last = poker.EventTitle(t.Name), t.Started
+	}
+	groups := GroupBy(ts, func(t poker.Tournament) string { return keys[t.Name] })
+	res := make([]SeriesEvent, 0, len(groups))
+	for _, g := range groups {
+		e := events[g.Key]
+		e.Group = g
+		res = append(res, *e)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Tournaments > res[j].Tournaments
+	})
+	return res
+}
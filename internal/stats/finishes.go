@@ -0,0 +1,150 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	// Bin is a histogram bucket, From inclusive and To exclusive.
+	Bin struct {
+		Label string  `json:"label"`
+		From  float64 `json:"from"`
+		To    float64 `json:"to"`
+		Count int     `json:"count"`
+	}
+)
+
+// FinishPercentile is the share of the field that finished ahead or level with us, 0..100.
+func FinishPercentile(t poker.Tournament) float64 {
+	if t.Players == 0 {
+		return 100
+	}
+	return 100 * float64(t.MyPlace) / float64(t.Players)
+}
+
+// FinishHistogram splits 0..100 finish percentiles into bins of equal width.
+func FinishHistogram(ts []poker.Tournament, bins int) []Bin {
+	if bins <= 0 {
+		bins = 10
+	}
+	width := 100 / float64(bins)
+	res := make([]Bin, bins)
+	for i := range res {
+		res[i].From = float64(i) * width
+		res[i].To = float64(i+1) * width
+		res[i].Label = fmt.Sprintf("%.0f-%.0f%%", res[i].From, res[i].To)
+	}
+	for _, t := range ts {
+		if t.MyPlace <= 0 {
+			continue
+		}
+		i := int(FinishPercentile(t) / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		res[i].Count++
+	}
+	return res
+}
+
+type (
+	FinishOptions struct {
+		Bins int
+		// DeepRun is the finish percentile at or below which a tournament is a deep run.
+		DeepRun float64
+		// Payouts tell the paid places, they are estimated by default.
+		Payouts poker.PayoutBook
+	}
+
+	FinishReport struct {
+		Tournaments    int       `json:"tournaments"`
+		Histogram      []Bin     `json:"histogram"`
+		ITM            int       `json:"itm"`
+		ITMRate        float64   `json:"itm_rate"`
+		BubbleBusts    int       `json:"bubble_busts"`
+		BubbleRate     float64   `json:"bubble_rate"`
+		FinalTables    int       `json:"final_tables"`
+		FinalTableRate float64   `json:"final_table_rate"`
+		Top3           int       `json:"top3"`
+		Top3Rate       float64   `json:"top3_rate"`
+		DeepRuns       []DeepRun `json:"deep_runs"`
+	}
+
+	DeepRun struct {
+		ID         string               `json:"id"`
+		Name       string               `json:"name"`
+		Type       poker.TournamentType `json:"type"`
+		Started    time.Time            `json:"started"`
+		Place      int                  `json:"place"`
+		Players    int                  `json:"players"`
+		PaidPlaces int                  `json:"paid_places"`
+		Percentile float64              `json:"percentile"`
+		Prize      float32              `json:"prize"`
+	}
+)
+
+const (
+	// bubbleShare is how far past the paid places, relative to their count, a bust is still a bubble.
+	bubbleShare = 0.1
+
+	DefaultDeepRun = 5.0
+)
+
+// Finishes reports where tournaments were finished relative to the field and the paid places.
+func Finishes(ts []poker.Tournament, o FinishOptions) FinishReport {
+	if o.DeepRun <= 0 {
+		o.DeepRun = DefaultDeepRun
+	}
+	r := FinishReport{
+		Histogram: FinishHistogram(ts, o.Bins),
+		DeepRuns:  make([]DeepRun, 0),
+	}
+	for _, t := range sortedByStart(ts) {
+		if t.MyPlace <= 0 || t.Players == 0 {
+			continue
+		}
+		r.Tournaments++
+		payout := o.Payouts.Payout(t)
+		paid := PaidPlaces(t, payout)
+		bubble := paid + max(1, int(math.Ceil(float64(paid)*bubbleShare)))
+		switch {
+		case InTheMoney(t, payout):
+			r.ITM++
+		case t.MyPlace <= bubble:
+			r.BubbleBusts++
+		}
+		finalTable := t.MyPlace <= t.TableSize()
+		if finalTable {
+			r.FinalTables++
+		}
+		if t.MyPlace <= 3 {
+			r.Top3++
+		}
+		percentile := FinishPercentile(t)
+		if finalTable || percentile <= o.DeepRun {
+			r.DeepRuns = append(r.DeepRuns, DeepRun{
+				ID:         t.ID,
+				Name:       t.Name,
+				Type:       t.Type,
+				Started:    t.Started,
+				Place:      t.MyPlace,
+				Players:    t.Players,
+				PaidPlaces: paid,
+				Percentile: percentile,
+				Prize:      t.MyPrize,
+			})
+		}
+	}
+	if r.Tournaments > 0 {
+		n := float64(r.Tournaments)
+		r.ITMRate = 100 * float64(r.ITM) / n
+		r.BubbleRate = 100 * float64(r.BubbleBusts) / n
+		r.FinalTableRate = 100 * float64(r.FinalTables) / n
+		r.Top3Rate = 100 * float64(r.Top3) / n
+	}
+	return r
+}
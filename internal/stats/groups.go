@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	// Group aggregates the results of the tournaments sharing a key.
+	Group struct {
+		Key         string  `json:"key"`
+		Tournaments int     `json:"tournaments"`
+		Cost        float64 `json:"cost"`
+		Prize       float64 `json:"prize"`
+		Profit      float64 `json:"profit"`
+		ROI         float64 `json:"roi"`
+		ITM         float64 `json:"itm"`
+		AvgBI       float64 `json:"avg_bi"`
+		AvgField    float64 `json:"avg_field"`
+	}
+	KeyFunc func(poker.Tournament) string
+	// KeysFunc puts a tournament into several groups at once.
+	KeysFunc func(poker.Tournament) []string
+
+	groupAcc struct {
+		Group
+		cashes  int
+		players int
+		bi      float64
+	}
+)
+
+func ByType(t poker.Tournament) string {
+	return string(t.Type)
+}
+
+func ByMonth(t poker.Tournament) string {
+	return t.Started.Format("2006-01")
+}
+
+func ByDay(t poker.Tournament) string {
+	return t.Started.Format("2006-01-02")
+}
+
+func ByPlayer(t poker.Tournament) string {
+	return t.Player
+}
+
+func ByAccount(t poker.Tournament) string {
+	return t.Account
+}
+
+// Untagged is the tag group of tournaments without tags.
+const Untagged = "(untagged)"
+
+// ByTag puts a tournament into the group of each of its tags.
+func ByTag(t poker.Tournament) []string {
+	if len(t.Tags) == 0 {
+		return []string{Untagged}
+	}
+	return t.Tags
+}
+
+// GroupKey returns the keys function by its name: type, month, day, tag,
+// player or account.
+func GroupKey(name string) (KeysFunc, error) {
+	switch name {
+	case "", "type":
+		return single(ByType), nil
+	case "month":
+		return single(ByMonth), nil
+	case "day":
+		return single(ByDay), nil
+	case "tag":
+		return ByTag, nil
+	case "player":
+		return single(ByPlayer), nil
+	case "account":
+		return single(ByAccount), nil
+	}
+	return nil, fmt.Errorf("unknown group %q", name)
+}
+
+func single(key KeyFunc) KeysFunc {
+	return func(t poker.Tournament) []string {
+		return []string{key(t)}
+	}
+}
+
+// GroupBy aggregates tournaments by key. Groups are sorted by key.
+func GroupBy(ts []poker.Tournament, key KeyFunc) []Group {
+	return GroupByKeys(ts, single(key))
+}
+
+// GroupByKeys aggregates tournaments that may belong to several groups,
+// so the groups can add up to more than the total.
+func GroupByKeys(ts []poker.Tournament, keys KeysFunc) []Group {
+	accs := make(map[string]*groupAcc)
+	for _, t := range ts {
+		for _, k := range keys(t) {
+			acc, ok := accs[k]
+			if !ok {
+				acc = &groupAcc{Group: Group{Key: k}}
+				accs[k] = acc
+			}
+			acc.add(t)
+		}
+	}
+	res := make([]Group, 0, len(accs))
+	for _, acc := range accs {
+		res = append(res, acc.group())
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+// Total aggregates all tournaments into a single group.
+func Total(ts []poker.Tournament) Group {
+	acc := groupAcc{Group: Group{Key: "total"}}
+	for _, t := range ts {
+		acc.add(t)
+	}
+	return acc.group()
+}
+
+func (acc *groupAcc) add(t poker.Tournament) {
+	acc.Tournaments++
+	acc.Cost += float64(t.Cost())
+	acc.Prize += float64(t.MyPrize)
+	acc.bi += float64(t.BI)
+	acc.players += t.Players
+	if t.MyPrize > 0 {
+		acc.cashes++
+	}
+}
+
+func (acc *groupAcc) group() Group {
+	g := acc.Group
+	g.Profit = g.Prize - g.Cost
+	if g.Cost > 0 {
+		g.ROI = 100 * g.Profit / g.Cost
+	}
+	if g.Tournaments > 0 {
+		g.ITM = 100 * float64(acc.cashes) / float64(g.Tournaments)
+		g.AvgBI = acc.bi / float64(g.Tournaments)
+		g.AvgField = float64(acc.players) / float64(g.Tournaments)
+	}
+	return g
+}
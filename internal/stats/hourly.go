@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	HourlyRate struct {
+		Key         string `json:"key"`
+		Tournaments int    `json:"tournaments"`
+		// KnownEnd counts tournaments with a recorded end, the rest use the assumed duration.
+		KnownEnd int     `json:"known_end"`
+		Profit   float64 `json:"profit"`
+		// Hours is wall clock time with at least one tournament running,
+		// so multi-tabled tournaments are not counted twice. The hours of
+		// teammates playing at the same time add up.
+		Hours float64 `json:"hours"`
+		// TableHours is the sum of all tournament durations.
+		TableHours   float64 `json:"table_hours"`
+		PerHour      float64 `json:"per_hour"`
+		PerTableHour float64 `json:"per_table_hour"`
+	}
+
+	HourlyReport struct {
+		Overall HourlyRate   `json:"overall"`
+		ByType  []HourlyRate `json:"by_type"`
+	}
+)
+
+// Hourly reports profit per hour overall and per tournament type. The hours of
+// a type only count the time tournaments of that type were running.
+func Hourly(ts []poker.Tournament, assumed time.Duration) HourlyReport {
+	if assumed <= 0 {
+		assumed = DefaultSessionDuration
+	}
+	byType := make(map[poker.TournamentType][]poker.Tournament)
+	for _, t := range ts {
+		byType[t.Type] = append(byType[t.Type], t)
+	}
+	r := HourlyReport{
+		Overall: hourlyRate("total", ts, assumed),
+		ByType:  make([]HourlyRate, 0, len(byType)),
+	}
+	for tt, list := range byType {
+		r.ByType = append(r.ByType, hourlyRate(string(tt), list, assumed))
+	}
+	sort.Slice(r.ByType, func(i, j int) bool {
+		return r.ByType[i].Key < r.ByType[j].Key
+	})
+	return r
+}
+
+func hourlyRate(key string, ts []poker.Tournament, assumed time.Duration) HourlyRate {
+	h := HourlyRate{Key: key, Tournaments: len(ts)}
+	var busy, total time.Duration
+	// cur is the end of the time counted so far for every player.
+	cur := make(map[int64]time.Time)
+	for _, t := range sortedByStart(ts) {
+		h.Profit += float64(t.Profit())
+		if !t.Finished.IsZero() {
+			h.KnownEnd++
+		}
+		end := tournamentEnd(t, assumed)
+		total += end.Sub(t.Started)
+		// Tournaments are sorted by start, so only the overhang past cur is new time.
+		start := t.Started
+		if start.Before(cur[t.PlayerID]) {
+			start = cur[t.PlayerID]
+		}
+		if end.After(start) {
+			busy += end.Sub(start)
+			cur[t.PlayerID] = end
+		}
+	}
+	h.Hours = busy.Hours()
+	h.TableHours = total.Hours()
+	if h.Hours > 0 {
+		h.PerHour = h.Profit / h.Hours
+	}
+	if h.TableHours > 0 {
+		h.PerTableHour = h.Profit / h.TableHours
+	}
+	return h
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func TestHourly(t *testing.T) {
+	unfinished := played(1, 12, 12, 10, 0)
+	unfinished.Finished = time.Time{}
+	tests := []struct {
+		name       string
+		ts         []poker.Tournament
+		hours      float64
+		tableHours float64
+		knownEnd   int
+	}{
+		{"overlapping", []poker.Tournament{played(1, 10, 12, 10, 0), played(1, 11, 13, 10, 0)}, 3, 4, 2},
+		{"within another", []poker.Tournament{played(1, 10, 13, 10, 0), played(1, 11, 12, 10, 0)}, 3, 4, 2},
+		{"apart", []poker.Tournament{played(1, 10, 11, 10, 0), played(1, 12, 13, 10, 0)}, 2, 2, 2},
+		{"teammates at once", []poker.Tournament{played(1, 10, 12, 10, 0), played(2, 10, 12, 10, 0)}, 4, 4, 2},
+		{"assumed end", []poker.Tournament{played(1, 11, 12.5, 10, 0), unfinished}, 2, 2.5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Hourly(tt.ts, time.Hour).Overall
+			if h.Hours != tt.hours || h.TableHours != tt.tableHours || h.KnownEnd != tt.knownEnd {
+				t.Errorf("hours %.2f, table hours %.2f, known end %d, want %.2f, %.2f, %d",
+					h.Hours, h.TableHours, h.KnownEnd, tt.hours, tt.tableHours, tt.knownEnd)
+			}
+			if want := h.Profit / tt.hours; h.PerHour != want {
+				t.Errorf("per hour %.2f, want %.2f", h.PerHour, want)
+			}
+		})
+	}
+}
+
+func TestHourlyByType(t *testing.T) {
+	big, turbo := played(1, 10, 12, 10, 40), played(1, 11, 13, 10, 0)
+	big.Type, turbo.Type = poker.Classic, poker.Turbo
+	r := Hourly([]poker.Tournament{big, turbo}, 0)
+	if r.Overall.Hours != 3 || r.Overall.Profit != 20 {
+		t.Errorf("overall %+v", r.Overall)
+	}
+	if len(r.ByType) != 2 || r.ByType[0].Key != string(poker.Classic) || r.ByType[0].Hours != 2 || r.ByType[1].Hours != 2 {
+		t.Errorf("by type %+v", r.ByType)
+	}
+}
@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+	"github.com/VOVAN1993/poker_hand/internal/poker/icm"
+)
+
+// AllInICM values the stack of the hero in money around a final table all-in.
+type AllInICM struct {
+	HandID  string    `json:"hand_id"`
+	Played  time.Time `json:"played"`
+	Players int       `json:"players"`
+	// Stacks are the chips of the table at the start of the hand.
+	Stacks    []float64 `json:"stacks"`
+	HeroStack float64   `json:"hero_stack"`
+	HeroAfter float64   `json:"hero_after"`
+	HeroAllIn bool      `json:"hero_all_in"`
+	// Before and After are the ICM equity of the hero at the start and at
+	// the end of the hand.
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"`
+}
+
+// FinalTableHands are the hands of the tournament at its final table. Rooms
+// seat the final table anew, so it is the last table the hero played at,
+// as long as the hero finished there.
+func FinalTableHands(t poker.Tournament, hands []poker.Hand) []poker.Hand {
+	var own []poker.Hand
+	for _, h := range hands {
+		if h.TournamentID == t.ID {
+			own = append(own, h)
+		}
+	}
+	if len(own) == 0 || t.MyPlace <= 0 || t.MyPlace > t.TableSize() {
+		return nil
+	}
+	sort.SliceStable(own, func(i, j int) bool {
+		return own[i].Played.Before(own[j].Played)
+	})
+	table := own[len(own)-1].Table
+	first := len(own)
+	for first > 0 && own[first-1].Table == table {
+		first--
+	}
+	return own[first:]
+}
+
+// FinalTableICM values every final table all-in for the hero with the
+// payout of the tournament, the players at the table being all that are left.
+func FinalTableICM(t poker.Tournament, hands []poker.Hand, payout poker.Payout) ([]AllInICM, error) {
+	res := make([]AllInICM, 0)
+	for _, h := range FinalTableHands(t, hands) {
+		hero := h.HeroSeat()
+		if hero < 0 || !h.AllIn() {
+			continue
+		}
+		before, err := icm.Equity(h.StacksBefore(), payout.Prizes)
+		if err != nil {
+			return nil, err
+		}
+		stacks := h.StacksAfter()
+		after, err := icm.Equity(stacks, payout.Prizes)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, AllInICM{
+			HandID:    h.ID,
+			Played:    h.Played,
+			Players:   len(h.Seats),
+			Stacks:    h.StacksBefore(),
+			HeroStack: h.Seats[hero].Stack,
+			HeroAfter: stacks[hero],
+			HeroAllIn: h.Seats[hero].AllIn,
+			Before:    before.Equity[hero],
+			After:     after.Equity[hero],
+			Change:    after.Equity[hero] - before.Equity[hero],
+		})
+	}
+	return res, nil
+}
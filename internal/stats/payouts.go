@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// TournamentPayout is where the money started in a tournament.
+type TournamentPayout struct {
+	ID          string    `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	Name        string    `json:"name"`
+	Started     time.Time `json:"started"`
+	Players     int       `json:"players"`
+	PrizePool   float64   `json:"prize_pool"`
+	Paid        int       `json:"paid"`
+	MinCash     float64   `json:"min_cash"`
+	Place       int       `json:"place"`
+	Prize       float64   `json:"prize"`
+	ITM         bool      `json:"itm"`
+	StructureID int64     `json:"structure_id,omitempty"`
+	Estimated   bool      `json:"estimated"`
+}
+
+// Payouts lists the paid places and min-cash of every tournament.
+func Payouts(ts []poker.Tournament, book poker.PayoutBook) []TournamentPayout {
+	res := make([]TournamentPayout, 0, len(ts))
+	for _, t := range sortedByStart(ts) {
+		p := book.Payout(t)
+		paid := PaidPlaces(t, p)
+		res = append(res, TournamentPayout{
+			ID:          t.ID,
+			AccountID:   t.AccountID,
+			Name:        t.Name,
+			Started:     t.Started,
+			Players:     t.Players,
+			PrizePool:   float64(t.TotalPrizePool),
+			Paid:        paid,
+			MinCash:     minCash(t, p, paid),
+			Place:       t.MyPlace,
+			Prize:       float64(t.MyPrize),
+			ITM:         InTheMoney(t, p),
+			StructureID: p.StructureID,
+			Estimated:   p.Estimated,
+		})
+	}
+	return res
+}
+
+// PaidPlaces is the paid places of the payout bounded by the result of the
+// tournament. No prize means the place was not paid. A prize means it was,
+// unless the tournament pays bounties: those come with any place.
+func PaidPlaces(t poker.Tournament, p poker.Payout) int {
+	switch {
+	case t.MyPlace <= 0:
+		return p.Paid
+	case t.MyPrize <= 0:
+		return min(p.Paid, t.MyPlace-1)
+	case t.BIBounty <= 0:
+		return max(p.Paid, t.MyPlace)
+	}
+	return p.Paid
+}
+
+// InTheMoney tells whether the tournament was finished in a paid place. A
+// prize of a bounty tournament without a known place may be bounties only,
+// it is no cash.
+func InTheMoney(t poker.Tournament, p poker.Payout) bool {
+	if t.MyPlace <= 0 {
+		return t.MyPrize > 0 && t.BIBounty <= 0
+	}
+	return t.MyPlace <= PaidPlaces(t, p)
+}
+
+// minCash is the prize of the last of the paid places. One past the payout
+// table was the place of the tournament, its prize is the min-cash.
+func minCash(t poker.Tournament, p poker.Payout, paid int) float64 {
+	switch {
+	case paid <= 0:
+		return 0
+	case paid <= len(p.Prizes):
+		return p.Prizes[paid-1]
+	}
+	return float64(t.MyPrize)
+}
@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// topThree pays 50/30/20 of the prize pool to the first three places.
+var topThree = poker.PayoutBook{Structures: []poker.PayoutStructure{{ID: 1, Name: "top 3", Places: []float64{50, 30, 20}}}}
+
+func payoutTournament(place int, prize, bounty float32) poker.Tournament {
+	return poker.Tournament{
+		ID:             "1",
+		BI:             10,
+		BIBounty:       bounty,
+		Players:        20,
+		TotalPrizePool: 100,
+		Started:        time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		MyPlace:        place,
+		MyPrize:        prize,
+	}
+}
+
+func TestPayouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       poker.Tournament
+		paid    int
+		minCash float64
+		itm     bool
+	}{
+		{"cash", payoutTournament(2, 30, 0), 3, 20, true},
+		{"bubble", payoutTournament(4, 0, 0), 3, 20, false},
+		{"prize past the table", payoutTournament(4, 15, 0), 4, 15, true},
+		{"no prize in the table", payoutTournament(3, 0, 0), 2, 30, false},
+		{"bounties only", payoutTournament(6, 5, 5), 3, 20, false},
+		{"bounty cash", payoutTournament(2, 40, 5), 3, 20, true},
+		{"bounty without a prize", payoutTournament(3, 0, 5), 2, 30, false},
+		{"unknown place", payoutTournament(0, 30, 0), 3, 20, true},
+		{"unknown place with bounties", payoutTournament(0, 5, 5), 3, 20, false},
+		{"unknown place without a prize", payoutTournament(0, 0, 0), 3, 20, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Payouts([]poker.Tournament{tt.t}, topThree)
+			if len(res) != 1 {
+				t.Fatalf("%d rows, want 1", len(res))
+			}
+			p := res[0]
+			if p.Paid != tt.paid || p.MinCash != tt.minCash || p.ITM != tt.itm {
+				t.Errorf("paid %d, min-cash %.2f, itm %v, want %d, %.2f, %v", p.Paid, p.MinCash, p.ITM, tt.paid, tt.minCash, tt.itm)
+			}
+		})
+	}
+}
+
+func TestFinishesCashes(t *testing.T) {
+	ts := []poker.Tournament{
+		payoutTournament(2, 30, 0),
+		payoutTournament(4, 0, 0),
+		payoutTournament(4, 15, 0),
+		payoutTournament(5, 5, 5),
+		payoutTournament(15, 0, 0),
+		payoutTournament(0, 30, 0),
+	}
+	r := Finishes(ts, FinishOptions{Payouts: topThree})
+	// The tournament without a place is not reported.
+	if r.Tournaments != 5 {
+		t.Errorf("tournaments = %d, want 5", r.Tournaments)
+	}
+	if r.ITM != 2 {
+		t.Errorf("itm = %d, want 2", r.ITM)
+	}
+	// Fourth is the bubble of three paid places, fifth with bounties only
+	// is past it.
+	if r.BubbleBusts != 1 {
+		t.Errorf("bubble busts = %d, want 1", r.BubbleBusts)
+	}
+}
+
+func TestFinishes(t *testing.T) {
+	finish := func(place, players int, prize float32) poker.Tournament {
+		t := payoutTournament(place, prize, 0)
+		t.Name, t.Players = "Hyper $10 [6-Max]", players
+		return t
+	}
+	tests := []struct {
+		name string
+		t    poker.Tournament
+		// want counts the tournament as itm, bubble, final table, top 3 and deep run.
+		want [5]bool
+	}{
+		{"winner", finish(1, 200, 50), [5]bool{true, false, true, true, true}},
+		{"final table", finish(5, 200, 0), [5]bool{false, false, true, false, true}},
+		{"bubble", finish(4, 200, 0), [5]bool{false, true, true, false, true}},
+		{"deep run", finish(8, 200, 0), [5]bool{false, false, false, false, true}},
+		{"field", finish(100, 200, 0), [5]bool{}},
+		{"short table", finish(3, 3, 20), [5]bool{true, false, true, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Finishes([]poker.Tournament{tt.t}, FinishOptions{Payouts: topThree})
+			got := [5]bool{r.ITM == 1, r.BubbleBusts == 1, r.FinalTables == 1, r.Top3 == 1, len(r.DeepRuns) == 1}
+			if r.Tournaments != 1 || got != tt.want {
+				t.Errorf("%+v, want itm, bubble, final table, top 3, deep run %v", r, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinishHistogram(t *testing.T) {
+	ts := []poker.Tournament{
+		payoutTournament(1, 50, 0),
+		payoutTournament(10, 0, 0),
+		payoutTournament(20, 0, 0),
+		payoutTournament(0, 0, 0),
+	}
+	// Places 1, 10 and 20 of 20 are 5, 50 and 100 percent, the last one in the top bin.
+	bins := FinishHistogram(ts, 4)
+	want := []int{1, 0, 1, 1}
+	for i, b := range bins {
+		if b.Count != want[i] {
+			t.Errorf("bin %s has %d, want %d", b.Label, b.Count, want[i])
+		}
+	}
+}
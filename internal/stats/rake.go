@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	RakeGroup struct {
+		Month       string  `json:"month,omitempty"`
+		Type        string  `json:"type,omitempty"`
+		Tournaments int     `json:"tournaments"`
+		Entries     int     `json:"entries"`
+		Cost        float64 `json:"cost"`
+		Rake        float64 `json:"rake"`
+		// Share is the part of the buy-ins that went to rake, in percent.
+		Share float64 `json:"share"`
+	}
+
+	RakeReport struct {
+		Total       RakeGroup   `json:"total"`
+		ByMonth     []RakeGroup `json:"by_month"`
+		ByType      []RakeGroup `json:"by_type"`
+		ByMonthType []RakeGroup `json:"by_month_type"`
+	}
+
+	// RakebackMonth compares the rakeback expected from a rule with what was paid.
+	RakebackMonth struct {
+		Month    string  `json:"month"`
+		RuleID   int64   `json:"rule_id"`
+		Rule     string  `json:"rule"`
+		Rake     float64 `json:"rake"`
+		Percent  float64 `json:"percent"`
+		Expected float64 `json:"expected"`
+		Actual   float64 `json:"actual"`
+		// Difference is what is still owed, negative when more was paid.
+		Difference float64 `json:"difference"`
+	}
+)
+
+// Rake reports rake paid per month, per type and per both.
+func Rake(ts []poker.Tournament) RakeReport {
+	var r RakeReport
+	byMonth := make(map[string]*RakeGroup)
+	byType := make(map[string]*RakeGroup)
+	byMonthType := make(map[[2]string]*RakeGroup)
+	for _, t := range ts {
+		month, tt := ByMonth(t), string(t.Type)
+		if byMonth[month] == nil {
+			byMonth[month] = &RakeGroup{Month: month}
+		}
+		if byType[tt] == nil {
+			byType[tt] = &RakeGroup{Type: tt}
+		}
+		key := [2]string{month, tt}
+		if byMonthType[key] == nil {
+			byMonthType[key] = &RakeGroup{Month: month, Type: tt}
+		}
+		for _, g := range []*RakeGroup{&r.Total, byMonth[month], byType[tt], byMonthType[key]} {
+			g.add(t)
+		}
+	}
+	r.Total.finish()
+	r.ByMonth = rakeGroups(byMonth)
+	r.ByType = rakeGroups(byType)
+	r.ByMonthType = rakeGroups(byMonthType)
+	return r
+}
+
+// MonthlyRake is the rake paid in the room per month, keyed by 2006-01.
+// An empty room takes all of them.
+func MonthlyRake(ts []poker.Tournament, room string) map[string]float64 {
+	res := make(map[string]float64)
+	for _, t := range ts {
+		if room != "" && t.Room != room {
+			continue
+		}
+		res[ByMonth(t)] += float64(t.RakePaid())
+	}
+	return res
+}
+
+// Rakeback generates the rakeback expected from every rule per month and
+// reconciles it with the rakeback entries of the ledger. Both the rake and
+// the entries are those of the rule's room.
+func Rakeback(ts []poker.Tournament, txs []poker.Transaction, rules []poker.RakebackRule) []RakebackMonth {
+	res := make([]RakebackMonth, 0)
+	for _, rule := range rules {
+		rake := MonthlyRake(ts, rule.Room)
+		actual := make(map[string]float64)
+		for _, tx := range txs {
+			if tx.Type != poker.Rakeback || (rule.Room != "" && tx.Room != rule.Room) {
+				continue
+			}
+			actual[tx.Date.Format("2006-01")] += float64(tx.SignedDollars())
+		}
+		months := make(map[string]bool)
+		for m := range rake {
+			months[m] = true
+		}
+		for m := range actual {
+			months[m] = true
+		}
+		for m := range months {
+			percent := rule.PercentFor(rake[m])
+			expected := rake[m] * percent / 100
+			res = append(res, RakebackMonth{
+				Month:      m,
+				RuleID:     rule.ID,
+				Rule:       rule.Name,
+				Rake:       rake[m],
+				Percent:    percent,
+				Expected:   expected,
+				Actual:     actual[m],
+				Difference: expected - actual[m],
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Month == res[j].Month {
+			return res[i].RuleID < res[j].RuleID
+		}
+		return res[i].Month < res[j].Month
+	})
+	return res
+}
+
+func (g *RakeGroup) add(t poker.Tournament) {
+	g.Tournaments++
+	g.Entries += 1 + t.Reentries
+	g.Cost += float64(t.TotalCost())
+	g.Rake += float64(t.RakePaid())
+}
+
+func (g *RakeGroup) finish() {
+	if g.Cost > 0 {
+		g.Share = 100 * g.Rake / g.Cost
+	}
+}
+
+func rakeGroups[K comparable](m map[K]*RakeGroup) []RakeGroup {
+	res := make([]RakeGroup, 0, len(m))
+	for _, g := range m {
+		g.finish()
+		res = append(res, *g)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Month == res[j].Month {
+			return res[i].Type < res[j].Type
+		}
+		return res[i].Month < res[j].Month
+	})
+	return res
+}
@@ -0,0 +1,285 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	XAxis  string
+	Bucket string
+
+	SeriesOptions struct {
+		XAxis XAxis
+		// Bucket is only used for the date axis.
+		Bucket Bucket
+		// MAWindow is the number of points in the moving average, 0 disables it.
+		MAWindow int
+	}
+
+	Point struct {
+		X           string    `json:"x"`
+		Date        time.Time `json:"date"`
+		Tournaments int       `json:"tournaments"`
+		Value       float64   `json:"value"`
+		MA          *float64  `json:"ma,omitempty"`
+		// Tags of the tournaments in the point.
+		Tags []string `json:"tags,omitempty"`
+		// Low and High bound the 95% confidence interval of Value, when known.
+		Low  *float64 `json:"low,omitempty"`
+		High *float64 `json:"high,omitempty"`
+	}
+
+	Series struct {
+		Name   string  `json:"name"`
+		XAxis  XAxis   `json:"x_axis"`
+		Bucket Bucket  `json:"bucket,omitempty"`
+		Points []Point `json:"points"`
+		// LedgerOmitted marks a bankroll of tournaments picked by more than
+		// their players, the ledger of the players left out.
+		LedgerOmitted bool `json:"ledger_omitted,omitempty"`
+	}
+)
+
+const (
+	AxisDate  XAxis = "date"
+	AxisIndex XAxis = "index"
+
+	BucketDay   Bucket = "day"
+	BucketWeek  Bucket = "week"
+	BucketMonth Bucket = "month"
+)
+
+func ParseXAxis(s string) (XAxis, error) {
+	switch XAxis(s) {
+	case "":
+		return AxisDate, nil
+	case AxisDate, AxisIndex:
+		return XAxis(s), nil
+	}
+	return "", fmt.Errorf("unknown x axis %q", s)
+}
+
+func ParseBucket(s string) (Bucket, error) {
+	switch Bucket(s) {
+	case "":
+		return BucketDay, nil
+	case BucketDay, BucketWeek, BucketMonth:
+		return Bucket(s), nil
+	}
+	return "", fmt.Errorf("unknown bucket %q", s)
+}
+
+// Start returns the beginning of the bucket containing t.
+func (b Bucket) Start(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch b {
+	case BucketWeek:
+		day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func (b Bucket) Label(t time.Time) string {
+	if b == BucketMonth {
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+// Bankroll is the cumulative profit together with the ledger entries.
+func Bankroll(ts []poker.Tournament, txs []poker.Transaction, o SeriesOptions) Series {
+	var total float64
+	events := append(tournamentEvents(ts), ledgerEvents(txs)...)
+	return buildSeries("Bankroll", events, o, func(e event) (Point, bool) {
+		if e.tournament != nil {
+			total += float64(e.tournament.Profit())
+		} else {
+			total += e.amount
+		}
+		return Point{Value: total}, true
+	})
+}
+
+// BankrollEV is the bankroll with the all-in luck of the evaluated
+// tournaments taken out of their results.
+func BankrollEV(ts []poker.Tournament, txs []poker.Transaction, evs []TournamentEV, o SeriesOptions) Series {
+	type key struct {
+		id      string
+		account int64
+	}
+	luck := make(map[key]float64, len(evs))
+	for _, e := range evs {
+		luck[key{e.TournamentID, e.AccountID}] += e.Luck
+	}
+	var total float64
+	events := append(tournamentEvents(ts), ledgerEvents(txs)...)
+	return buildSeries("Bankroll EV", events, o, func(e event) (Point, bool) {
+		if e.tournament != nil {
+			total += float64(e.tournament.Profit()) - luck[key{e.tournament.ID, e.tournament.AccountID}]
+		} else {
+			total += e.amount
+		}
+		return Point{Value: total}, true
+	})
+}
+
+// ROI is the cumulative return on investment in percent with its confidence interval.
+func ROI(ts []poker.Tournament, o SeriesOptions) Series {
+	var acc roiAcc
+	return buildSeries("ROI", tournamentEvents(ts), o, func(e event) (Point, bool) {
+		if !countsForROI(*e.tournament) {
+			return Point{}, false
+		}
+		acc.add(*e.tournament)
+		if acc.cost == 0 {
+			return Point{}, false
+		}
+		p := Point{Value: acc.roi()}
+		if acc.n > 1 {
+			low, high := acc.confidence()
+			p.Low, p.High = &low, &high
+		}
+		return p, true
+	})
+}
+
+// Volume is the number of tournaments played per point.
+func Volume(ts []poker.Tournament, o SeriesOptions) Series {
+	s := buildSeries("Volume", tournamentEvents(ts), SeriesOptions{XAxis: o.XAxis, Bucket: o.Bucket}, func(event) (Point, bool) {
+		return Point{}, true
+	})
+	for i := range s.Points {
+		s.Points[i].Value = float64(s.Points[i].Tournaments)
+	}
+	movingAverage(s.Points, o.MAWindow)
+	return s
+}
+
+type event struct {
+	at         time.Time
+	tournament *poker.Tournament
+	// amount of a ledger entry, when tournament is nil.
+	amount float64
+}
+
+func tournamentEvents(ts []poker.Tournament) []event {
+	res := make([]event, 0, len(ts))
+	for i := range ts {
+		res = append(res, event{at: ts[i].Started, tournament: &ts[i]})
+	}
+	return res
+}
+
+func ledgerEvents(txs []poker.Transaction) []event {
+	res := make([]event, 0, len(txs))
+	for _, tx := range txs {
+		res = append(res, event{at: tx.Date, amount: float64(tx.SignedDollars())})
+	}
+	return res
+}
+
+// buildSeries feeds events in time order to value and emits a point per
+// tournament or per bucket, holding the last values reported in it.
+// On the index axis ledger entries are folded into the preceding point.
+func buildSeries(name string, events []event, o SeriesOptions, value func(event) (Point, bool)) Series {
+	s := Series{Name: name, XAxis: o.XAxis, Points: make([]Point, 0)}
+	if s.XAxis == "" {
+		s.XAxis = AxisDate
+	}
+	if s.XAxis == AxisDate {
+		s.Bucket = o.Bucket
+		if s.Bucket == "" {
+			s.Bucket = BucketDay
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+	for _, e := range events {
+		p, ok := value(e)
+		if !ok {
+			continue
+		}
+		if e.tournament != nil {
+			p.Tournaments = 1
+			p.Tags = e.tournament.Tags
+		}
+		n := len(s.Points)
+		if s.XAxis == AxisIndex {
+			p.Date = e.at
+			if e.tournament == nil && n > 0 {
+				p.Tournaments, p.X, p.Tags = s.Points[n-1].Tournaments, s.Points[n-1].X, s.Points[n-1].Tags
+				s.Points[n-1] = p
+				continue
+			}
+			p.X = fmt.Sprint(n + 1)
+			s.Points = append(s.Points, p)
+			continue
+		}
+		p.Date = s.Bucket.Start(e.at)
+		p.X = s.Bucket.Label(p.Date)
+		if n > 0 && s.Points[n-1].Date.Equal(p.Date) {
+			p.Tournaments += s.Points[n-1].Tournaments
+			p.Tags = mergeTags(s.Points[n-1].Tags, p.Tags)
+			s.Points[n-1] = p
+			continue
+		}
+		s.Points = append(s.Points, p)
+	}
+	movingAverage(s.Points, o.MAWindow)
+	return s
+}
+
+// mergeTags unions two sorted tag lists.
+func mergeTags(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	res := make([]string, 0, len(a)+len(b))
+	res = append(res, a...)
+	for _, tag := range b {
+		if !slices.Contains(res, tag) {
+			res = append(res, tag)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+func movingAverage(points []Point, window int) {
+	if window <= 1 {
+		return
+	}
+	var sum float64
+	for i := range points {
+		sum += points[i].Value
+		if i >= window {
+			sum -= points[i-window].Value
+		}
+		n := min(i+1, window)
+		ma := sum / float64(n)
+		points[i].MA = &ma
+	}
+}
+
+func sortedByStart(ts []poker.Tournament) []poker.Tournament {
+	res := make([]poker.Tournament, len(ts))
+	copy(res, ts)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Started.Before(res[j].Started)
+	})
+	return res
+}
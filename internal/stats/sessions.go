@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	SessionOptions struct {
+		// Gap is the longest break between tournaments inside one session.
+		Gap time.Duration
+		// Duration is assumed for tournaments without a known end.
+		Duration time.Duration
+	}
+
+	// Session is the play of one player, teammates playing at the same
+	// time have sessions of their own.
+	Session struct {
+		ID          string             `json:"id"`
+		PlayerID    int64              `json:"player_id"`
+		Player      string             `json:"player,omitempty"`
+		Start       time.Time          `json:"start"`
+		End         time.Time          `json:"end"`
+		Tournaments int                `json:"tournaments"`
+		Reentries   int                `json:"reentries"`
+		Cost        float64            `json:"cost"`
+		Prize       float64            `json:"prize"`
+		Profit      float64            `json:"profit"`
+		ROI         float64            `json:"roi"`
+		MaxTables   int                `json:"max_tables"`
+		List        []poker.Tournament `json:"list,omitempty"`
+	}
+)
+
+const (
+	DefaultSessionGap      = 2 * time.Hour
+	DefaultSessionDuration = time.Hour
+
+	sessionIDLayout = "20060102-1504"
+)
+
+// Sessions groups the tournaments of every player into playing sessions: a
+// tournament starting later than Gap after every earlier one of the player
+// has ended opens a new session. Sessions are ordered by start.
+func Sessions(ts []poker.Tournament, o SessionOptions) []Session {
+	if o.Gap <= 0 {
+		o.Gap = DefaultSessionGap
+	}
+	if o.Duration <= 0 {
+		o.Duration = DefaultSessionDuration
+	}
+	res := make([]Session, 0)
+	for _, list := range byPlayer(ts) {
+		res = append(res, playerSessions(list, o)...)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Start.Equal(res[j].Start) {
+			return res[i].PlayerID < res[j].PlayerID
+		}
+		return res[i].Start.Before(res[j].Start)
+	})
+	return res
+}
+
+// byPlayer splits the tournaments by player.
+func byPlayer(ts []poker.Tournament) map[int64][]poker.Tournament {
+	res := make(map[int64][]poker.Tournament)
+	for _, t := range ts {
+		res[t.PlayerID] = append(res[t.PlayerID], t)
+	}
+	return res
+}
+
+func playerSessions(ts []poker.Tournament, o SessionOptions) []Session {
+	var (
+		res []Session
+		cur *Session
+	)
+	for _, t := range sortedByStart(ts) {
+		end := tournamentEnd(t, o.Duration)
+		if cur == nil || t.Started.After(cur.End.Add(o.Gap)) {
+			if cur != nil {
+				res = append(res, cur.finish(o.Duration))
+			}
+			cur = &Session{
+				ID:       fmt.Sprintf("%s-%d", t.Started.Format(sessionIDLayout), t.PlayerID),
+				PlayerID: t.PlayerID,
+				Player:   t.Player,
+				Start:    t.Started,
+				End:      end,
+			}
+		}
+		if end.After(cur.End) {
+			cur.End = end
+		}
+		cur.Tournaments++
+		cur.Reentries += t.Reentries
+		cur.Cost += float64(t.Cost())
+		cur.Prize += float64(t.MyPrize)
+		cur.List = append(cur.List, t)
+	}
+	if cur != nil {
+		res = append(res, cur.finish(o.Duration))
+	}
+	return res
+}
+
+func (s *Session) finish(duration time.Duration) Session {
+	s.Profit = s.Prize - s.Cost
+	if s.Cost > 0 {
+		s.ROI = 100 * s.Profit / s.Cost
+	}
+	s.MaxTables = maxConcurrent(s.List, duration)
+	return *s
+}
+
+// tournamentEnd is the known end of the tournament or its start plus the assumed duration.
+func tournamentEnd(t poker.Tournament, assumed time.Duration) time.Time {
+	if !t.Finished.IsZero() {
+		return t.Finished
+	}
+	return t.Started.Add(assumed)
+}
+
+// maxConcurrent is the largest number of tournaments running at the same time.
+func maxConcurrent(ts []poker.Tournament, duration time.Duration) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	edges := make([]edge, 0, 2*len(ts))
+	for _, t := range ts {
+		edges = append(edges, edge{t.Started, 1}, edge{tournamentEnd(t, duration), -1})
+	}
+	// Ends go first, so back-to-back tournaments don't overlap.
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].at.Equal(edges[j].at) {
+			return edges[i].delta < edges[j].delta
+		}
+		return edges[i].at.Before(edges[j].at)
+	})
+	cur, res := 0, 0
+	for _, e := range edges {
+		cur += e.delta
+		res = max(res, cur)
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+var sessionDay = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+// played is a tournament of the player from one hour of the day to another.
+func played(player int64, from, to float64, cost, prize float32) poker.Tournament {
+	start := sessionDay.Add(time.Duration(from * float64(time.Hour)))
+	return poker.Tournament{
+		ID:       start.Format(time.TimeOnly),
+		PlayerID: player,
+		BI:       cost,
+		MyPrize:  prize,
+		Started:  start,
+		Finished: sessionDay.Add(time.Duration(to * float64(time.Hour))),
+		Duration: time.Duration((to - from) * float64(time.Hour)),
+	}
+}
+
+func TestSessions(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   []poker.Tournament
+		// want holds the tournaments and the max tables of every session.
+		want [][2]int
+	}{
+		{"one session", []poker.Tournament{played(1, 10, 12, 10, 0), played(1, 11, 13, 10, 30), played(1, 14, 15, 10, 0)}, [][2]int{{3, 2}}},
+		{"gap opens a session", []poker.Tournament{played(1, 10, 11, 10, 0), played(1, 13.5, 14, 10, 0)}, [][2]int{{1, 1}, {1, 1}}},
+		{"back to back", []poker.Tournament{played(1, 10, 11, 10, 0), played(1, 11, 12, 10, 0)}, [][2]int{{2, 1}}},
+		{"teammates apart", []poker.Tournament{played(1, 10, 12, 10, 0), played(2, 10.5, 12, 10, 0), played(1, 11, 12, 10, 0)}, [][2]int{{2, 2}, {1, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := Sessions(tt.ts, SessionOptions{})
+			if len(sessions) != len(tt.want) {
+				t.Fatalf("%d sessions, want %d", len(sessions), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := [2]int{sessions[i].Tournaments, sessions[i].MaxTables}; got != want {
+					t.Errorf("session %d has %d tournaments on %d tables, want %v", i, got[0], got[1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionsOfTeammates(t *testing.T) {
+	sessions := Sessions([]poker.Tournament{played(2, 10, 11, 10, 0), played(1, 10, 11, 10, 30)}, SessionOptions{})
+	if len(sessions) != 2 || sessions[0].ID == sessions[1].ID {
+		t.Fatalf("sessions %+v, want two with their own ids", sessions)
+	}
+	if sessions[0].PlayerID != 1 || sessions[0].Profit != 20 || sessions[1].PlayerID != 2 || sessions[1].Profit != -10 {
+		t.Errorf("sessions %+v", sessions)
+	}
+}
+
+func TestSessionsOptions(t *testing.T) {
+	unfinished := played(1, 11, 11, 10, 0)
+	unfinished.Finished = time.Time{}
+	ts := []poker.Tournament{played(1, 10, 11, 10, 40), unfinished, played(1, 13, 14, 10, 0)}
+	tests := []struct {
+		name string
+		o    SessionOptions
+		// ends holds the end hour of every session.
+		ends []float64
+	}{
+		{"defaults", SessionOptions{}, []float64{14}},
+		{"short gap", SessionOptions{Gap: 30 * time.Minute}, []float64{12, 14}},
+		{"long assumed duration", SessionOptions{Gap: 30 * time.Minute, Duration: 3 * time.Hour}, []float64{14}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := Sessions(ts, tt.o)
+			if len(sessions) != len(tt.ends) {
+				t.Fatalf("%d sessions, want %d", len(sessions), len(tt.ends))
+			}
+			for i, end := range tt.ends {
+				if want := sessionDay.Add(time.Duration(end * float64(time.Hour))); !sessions[i].End.Equal(want) {
+					t.Errorf("session %d ends at %s, want %s", i, sessions[i].End.Format(time.TimeOnly), want.Format(time.TimeOnly))
+				}
+			}
+			if first := sessions[0]; first.Tournaments == 3 && (first.Profit != 10 || first.ROI != 100.0/3) {
+				t.Errorf("profit %.2f, roi %.2f, want 10, 33.33", first.Profit, first.ROI)
+			}
+		})
+	}
+}
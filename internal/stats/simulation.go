@@ -0,0 +1,216 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	SimulationOptions struct {
+		// Bankroll is the starting bankroll in $.
+		Bankroll float64 `json:"bankroll"`
+		// BuyIn is the buy-in played in the simulation, the average historical cost by default.
+		BuyIn       float64 `json:"buy_in"`
+		Tournaments int     `json:"tournaments"`
+		Paths       int     `json:"paths"`
+		Seed        int64   `json:"seed"`
+		// SamplePaths is the number of raw paths returned alongside the percentiles.
+		SamplePaths int `json:"sample_paths"`
+	}
+
+	Simulation struct {
+		Options  SimulationOptions `json:"options"`
+		Outcomes int               `json:"outcomes"`
+		// ROI of the sampled outcomes, the expectation of every simulated tournament.
+		ROI        float64 `json:"roi"`
+		RiskOfRuin float64 `json:"risk_of_ruin"`
+		// Final bankroll percentiles, ruined paths count as zero.
+		Final Percentiles `json:"final"`
+		// Downswing is the deepest peak-to-valley drop of a path in $.
+		Downswing   Percentiles `json:"downswing"`
+		DownswingBI Percentiles `json:"downswing_bi"`
+		// Checkpoints hold the bankroll percentiles over all paths after the given number of tournaments.
+		Checkpoints []Checkpoint `json:"checkpoints"`
+		Samples     [][]float64  `json:"samples"`
+	}
+
+	Percentiles struct {
+		P5   float64 `json:"p5"`
+		P25  float64 `json:"p25"`
+		P50  float64 `json:"p50"`
+		P75  float64 `json:"p75"`
+		P95  float64 `json:"p95"`
+		Mean float64 `json:"mean"`
+	}
+
+	Checkpoint struct {
+		Tournaments int `json:"tournaments"`
+		Percentiles
+	}
+)
+
+const (
+	DefaultSimulationTournaments = 1000
+	DefaultSimulationPaths       = 1000
+	defaultSamplePaths           = 5
+	simulationCheckpoints        = 50
+
+	MaxSimulationTournaments = 1_000_000
+	MaxSimulationPaths       = 100_000
+	MaxSamplePaths           = 100
+	// maxSamplePoints bounds the raw bankrolls returned, a point per
+	// tournament of every sample path.
+	maxSamplePoints = 10_000_000
+	// maxSimulationDraws bounds the outcomes drawn, one per tournament of
+	// every path.
+	maxSimulationDraws = 100_000_000
+)
+
+// Validate checks the sizes of the simulation, zero ones take the defaults.
+func (o SimulationOptions) Validate() error {
+	switch {
+	case o.Tournaments < 0 || o.Tournaments > MaxSimulationTournaments:
+		return fmt.Errorf("tournaments must be between 0 and %d", MaxSimulationTournaments)
+	case o.Paths < 0 || o.Paths > MaxSimulationPaths:
+		return fmt.Errorf("paths must be between 0 and %d", MaxSimulationPaths)
+	case o.SamplePaths < 0 || o.SamplePaths > MaxSamplePaths:
+		return fmt.Errorf("samples must be between 0 and %d", MaxSamplePaths)
+	case o.SamplePaths*o.Tournaments > maxSamplePoints:
+		return fmt.Errorf("samples times tournaments must be at most %d", maxSamplePoints)
+	case o.Paths*o.Tournaments > maxSimulationDraws:
+		return fmt.Errorf("paths times tournaments must be at most %d", maxSimulationDraws)
+	}
+	return nil
+}
+
+// Simulate plays random paths drawing tournament results, as multiples of the buy-in,
+// from the given history. A path is ruined once it can't afford the next buy-in,
+// a bankroll below the buy-in is ruined from the start.
+func Simulate(ctx context.Context, ts []poker.Tournament, o SimulationOptions) (Simulation, error) {
+	if err := o.Validate(); err != nil {
+		return Simulation{}, err
+	}
+	var (
+		multiples []float64
+		acc       roiAcc
+	)
+	for _, t := range ts {
+		if !countsForROI(t) || t.Cost() <= 0 {
+			continue
+		}
+		acc.add(t)
+		multiples = append(multiples, float64(t.MyPrize)/float64(t.Cost()))
+	}
+	if len(multiples) == 0 {
+		return Simulation{}, errors.New("no paid tournaments to sample outcomes from")
+	}
+	if o.BuyIn <= 0 {
+		o.BuyIn = acc.avgCost()
+	}
+	if o.Bankroll <= 0 {
+		return Simulation{}, errors.New("starting bankroll must be positive")
+	}
+	if o.Tournaments <= 0 {
+		o.Tournaments = DefaultSimulationTournaments
+	}
+	if o.Paths <= 0 {
+		o.Paths = DefaultSimulationPaths
+	}
+	if o.SamplePaths <= 0 {
+		o.SamplePaths = defaultSamplePaths
+	}
+	o.SamplePaths = min(o.SamplePaths, o.Paths)
+	if err := o.Validate(); err != nil {
+		return Simulation{}, err
+	}
+
+	step := max(1, o.Tournaments/simulationCheckpoints)
+	checkpoints := make([][]float64, 0, o.Tournaments/step)
+	for n := step; n <= o.Tournaments; n += step {
+		checkpoints = append(checkpoints, make([]float64, o.Paths))
+	}
+
+	rnd := rand.New(rand.NewSource(o.Seed))
+	sim := Simulation{Options: o, Outcomes: len(multiples), ROI: acc.roi()}
+	finals := make([]float64, o.Paths)
+	downswings := make([]float64, o.Paths)
+	ruined := 0
+	for p := 0; p < o.Paths; p++ {
+		if err := ctx.Err(); err != nil {
+			return Simulation{}, err
+		}
+		bankroll, peak, downswing := o.Bankroll, o.Bankroll, 0.0
+		if bankroll < o.BuyIn {
+			ruined++
+			bankroll = 0
+		}
+		var sample []float64
+		if p < o.SamplePaths {
+			sample = append(make([]float64, 0, o.Tournaments+1), bankroll)
+		}
+		for n := 1; n <= o.Tournaments; n++ {
+			if bankroll >= o.BuyIn {
+				bankroll += o.BuyIn * (multiples[rnd.Intn(len(multiples))] - 1)
+				peak = math.Max(peak, bankroll)
+				downswing = math.Max(downswing, peak-bankroll)
+				if bankroll < o.BuyIn {
+					ruined++
+					bankroll = 0
+				}
+			}
+			if sample != nil {
+				sample = append(sample, bankroll)
+			}
+			if n%step == 0 && n/step <= len(checkpoints) {
+				checkpoints[n/step-1][p] = bankroll
+			}
+		}
+		finals[p] = bankroll
+		downswings[p] = downswing
+		if sample != nil {
+			sim.Samples = append(sim.Samples, sample)
+		}
+	}
+
+	sim.RiskOfRuin = 100 * float64(ruined) / float64(o.Paths)
+	sim.Final = percentiles(finals)
+	sim.Downswing = percentiles(downswings)
+	sim.DownswingBI = scalePercentiles(sim.Downswing, 1/o.BuyIn)
+	for i, values := range checkpoints {
+		sim.Checkpoints = append(sim.Checkpoints, Checkpoint{
+			Tournaments: (i + 1) * step,
+			Percentiles: percentiles(values),
+		})
+	}
+	return sim, nil
+}
+
+// percentiles sorts values in place.
+func percentiles(values []float64) Percentiles {
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	p := Percentiles{
+		P5:  quantile(values, 0.05),
+		P25: quantile(values, 0.25),
+		P50: quantile(values, 0.5),
+		P75: quantile(values, 0.75),
+		P95: quantile(values, 0.95),
+	}
+	if len(values) > 0 {
+		p.Mean = sum / float64(len(values))
+	}
+	return p
+}
+
+func scalePercentiles(p Percentiles, k float64) Percentiles {
+	return Percentiles{P5: p.P5 * k, P25: p.P25 * k, P50: p.P50 * k, P75: p.P75 * k, P95: p.P95 * k, Mean: p.Mean * k}
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func TestSimulationOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		o    SimulationOptions
+		ok   bool
+	}{
+		{"defaults", SimulationOptions{}, true},
+		{"largest", SimulationOptions{Tournaments: 1000, Paths: MaxSimulationPaths, SamplePaths: MaxSamplePaths}, true},
+		{"negative paths", SimulationOptions{Paths: -1}, false},
+		{"too many tournaments", SimulationOptions{Tournaments: MaxSimulationTournaments + 1}, false},
+		{"too many samples", SimulationOptions{SamplePaths: MaxSamplePaths + 1}, false},
+		{"too many sample points", SimulationOptions{Tournaments: MaxSimulationTournaments, SamplePaths: MaxSamplePaths}, false},
+		{"too many draws", SimulationOptions{Tournaments: MaxSimulationTournaments, Paths: MaxSimulationPaths}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.o.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: error %v", tt.name, err)
+		}
+	}
+}
+
+func TestSimulateRuin(t *testing.T) {
+	ts := []poker.Tournament{{BI: 10, MyPrize: 0}, {BI: 10, MyPrize: 30}}
+	tests := []struct {
+		name     string
+		bankroll float64
+		ruin     float64
+	}{
+		{"below the buy-in", 5, 100},
+		{"deep", 1e6, 0},
+	}
+	for _, tt := range tests {
+		sim, err := Simulate(context.Background(), ts, SimulationOptions{Bankroll: tt.bankroll, Tournaments: 100, Paths: 50, Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sim.RiskOfRuin != tt.ruin {
+			t.Errorf("%s: risk of ruin %.1f, want %.1f", tt.name, sim.RiskOfRuin, tt.ruin)
+		}
+	}
+	if _, err := Simulate(context.Background(), ts, SimulationOptions{Bankroll: 100, Paths: MaxSimulationPaths, Tournaments: 10_000}); err == nil {
+		t.Error("a simulation of 1e9 draws ran")
+	}
+}
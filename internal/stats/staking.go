@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	// StakeRow splits one tournament of a deal between the player and the backer.
+	StakeRow struct {
+		TournamentID string    `json:"tournament_id"`
+		AccountID    int64     `json:"account_id"`
+		Started      time.Time `json:"started"`
+		Cost         float64   `json:"cost"`
+		Prize        float64   `json:"prize"`
+		// BackerCost is the sold part of the cost, BackerPaid what the backer
+		// paid for it with the markup.
+		BackerCost  float64 `json:"backer_cost"`
+		BackerPaid  float64 `json:"backer_paid"`
+		BackerPrize float64 `json:"backer_prize"`
+		// PlayerShare is the result of the action the player kept plus the
+		// markup premium, the cut of the backer's profit comes at the settlement.
+		PlayerShare float64 `json:"player_share"`
+		// Makeup is what the backer is down after the tournament.
+		Makeup float64 `json:"makeup"`
+	}
+
+	// Settlement closes a period of a deal.
+	Settlement struct {
+		Period      string    `json:"period"`
+		From        time.Time `json:"from"`
+		To          time.Time `json:"to,omitempty"`
+		Tournaments int       `json:"tournaments"`
+		BackerPaid  float64   `json:"backer_paid"`
+		BackerPrize float64   `json:"backer_prize"`
+		// MakeupBefore is carried in from the previous period, MakeupAfter is
+		// carried on to the next one.
+		MakeupBefore float64 `json:"makeup_before"`
+		MakeupAfter  float64 `json:"makeup_after"`
+		// Profit is what the backer is up once the makeup is cleared, the
+		// player gets PlayerCut of it.
+		Profit    float64 `json:"profit"`
+		PlayerCut float64 `json:"player_cut"`
+		// PlayerShare is the result of the period for the player.
+		PlayerShare float64 `json:"player_share"`
+		// Owed is what the player sends the backer, the backer's result of the
+		// period: prizes less the markup paid and the cut. Negative when the
+		// backer pays.
+		Owed float64 `json:"owed"`
+		// Open periods have not ended yet and are not settled.
+		Open bool `json:"open"`
+	}
+
+	StakingReport struct {
+		Deal        poker.Deal   `json:"deal"`
+		Rows        []StakeRow   `json:"rows"`
+		Settlements []Settlement `json:"settlements"`
+		PlayerShare float64      `json:"player_share"`
+		BackerShare float64      `json:"backer_share"`
+		// Makeup is what the backer is down now.
+		Makeup float64 `json:"makeup"`
+	}
+)
+
+// Staking applies a deal to the tournaments it covers and settles it per
+// period. Periods ending after now are left open.
+func Staking(d poker.Deal, ts []poker.Tournament, now time.Time) StakingReport {
+	r := StakingReport{Deal: d, Rows: make([]StakeRow, 0), Settlements: make([]Settlement, 0)}
+	sold, cut := d.Sold/100, d.PlayerCut/100
+	// balance is the backer's result since the makeup was last cleared.
+	var balance float64
+	var cur *Settlement
+	settle := func() {
+		if cur == nil {
+			return
+		}
+		cur.Open = cur.To.IsZero() || cur.To.After(now)
+		if !cur.Open {
+			switch {
+			case balance > 0:
+				cur.Profit = balance
+				cur.PlayerCut = balance * cut
+				balance = 0
+			case d.Makeup == poker.MakeupReset:
+				balance = 0
+			}
+		}
+		cur.MakeupAfter = max(0, -balance)
+		cur.PlayerShare += cur.PlayerCut
+		cur.Owed = cur.BackerPrize - cur.BackerPaid - cur.PlayerCut
+		r.PlayerShare += cur.PlayerShare
+		r.BackerShare += cur.Owed
+		r.Settlements = append(r.Settlements, *cur)
+		cur = nil
+	}
+	for _, t := range sortedByStart(ts) {
+		if !d.Covers(t.Started) {
+			continue
+		}
+		from, to := dealPeriod(d, t.Started)
+		if cur != nil && !cur.From.Equal(from) {
+			settle()
+		}
+		if cur == nil {
+			cur = &Settlement{Period: periodLabel(d, from), From: from, To: to, MakeupBefore: max(0, -balance)}
+		}
+		row := StakeRow{
+			TournamentID: t.ID,
+			AccountID:    t.AccountID,
+			Started:      t.Started,
+			Cost:         float64(t.Cost()),
+			Prize:        float64(t.MyPrize),
+		}
+		row.BackerCost = row.Cost * sold
+		row.BackerPaid = row.BackerCost * d.Markup
+		row.BackerPrize = row.Prize * sold
+		row.PlayerShare = (row.Prize-row.Cost)*(1-sold) + row.BackerPaid - row.BackerCost
+		balance += row.BackerPrize - row.BackerPaid
+		row.Makeup = max(0, -balance)
+
+		cur.Tournaments++
+		cur.BackerPaid += row.BackerPaid
+		cur.BackerPrize += row.BackerPrize
+		cur.PlayerShare += row.PlayerShare
+		r.Rows = append(r.Rows, row)
+	}
+	settle()
+	r.Makeup = max(0, -balance)
+	return r
+}
+
+// dealPeriod is the settlement period holding the start of a tournament. An
+// open deal settled once has no end.
+func dealPeriod(d poker.Deal, started time.Time) (time.Time, time.Time) {
+	var from, to time.Time
+	switch d.Period {
+	case poker.PeriodWeek:
+		from = BucketWeek.Start(started)
+		to = from.AddDate(0, 0, 7)
+	case poker.PeriodMonth:
+		from = BucketMonth.Start(started)
+		to = from.AddDate(0, 1, 0)
+	default:
+		return d.Start, d.End
+	}
+	if from.Before(d.Start) {
+		from = d.Start
+	}
+	if !d.End.IsZero() && to.After(d.End) {
+		to = d.End
+	}
+	return from, to
+}
+
+func periodLabel(d poker.Deal, from time.Time) string {
+	switch d.Period {
+	case poker.PeriodWeek:
+		return BucketWeek.Label(BucketWeek.Start(from))
+	case poker.PeriodMonth:
+		return BucketMonth.Label(from)
+	}
+	return "deal"
+}
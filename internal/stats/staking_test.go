@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func TestStakingMakeup(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 12, 0, 0, 0, time.UTC)
+	// The backer buys half at no markup: down 50 in January, up 100 in February.
+	ts := []poker.Tournament{
+		{ID: "1", BI: 100, Started: jan},
+		{ID: "2", BI: 100, MyPrize: 300, Started: feb},
+	}
+	tests := []struct {
+		name   string
+		makeup poker.MakeupRule
+		now    time.Time
+		// carried is the makeup after January, profit, cut and owed are of February.
+		carried, profit, cut, owed float64
+		open                       bool
+	}{
+		{"carry", poker.MakeupCarry, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), 50, 50, 25, 75, false},
+		{"reset", poker.MakeupReset, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), 0, 100, 50, 50, false},
+		{"open period", poker.MakeupCarry, time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), 50, 0, 0, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := poker.Deal{
+				PlayerID:  1,
+				Backer:    "backer",
+				Sold:      50,
+				Markup:    1,
+				PlayerCut: 50,
+				Makeup:    tt.makeup,
+				Period:    poker.PeriodMonth,
+				Start:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+			r := Staking(d, ts, tt.now)
+			if len(r.Settlements) != 2 {
+				t.Fatalf("%d settlements, want 2", len(r.Settlements))
+			}
+			s := r.Settlements[1]
+			if r.Settlements[0].MakeupAfter != tt.carried || s.MakeupBefore != tt.carried {
+				t.Errorf("makeup after %.2f, before %.2f, want %.2f", r.Settlements[0].MakeupAfter, s.MakeupBefore, tt.carried)
+			}
+			if s.Profit != tt.profit || s.PlayerCut != tt.cut || s.Owed != tt.owed || s.Open != tt.open {
+				t.Errorf("profit %.2f, cut %.2f, owed %.2f, open %v, want %.2f, %.2f, %.2f, %v",
+					s.Profit, s.PlayerCut, s.Owed, s.Open, tt.profit, tt.cut, tt.owed, tt.open)
+			}
+			if r.Makeup != 0 {
+				t.Errorf("makeup %.2f, want 0", r.Makeup)
+			}
+		})
+	}
+}
+
+func TestStakingMarkup(t *testing.T) {
+	d := poker.Deal{Sold: 50, Markup: 1.2, Makeup: poker.MakeupCarry, Period: poker.PeriodDeal, Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := Staking(d, []poker.Tournament{{ID: "1", BI: 100, Started: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}}, time.Now())
+	if len(r.Rows) != 1 {
+		t.Fatalf("%d rows, want 1", len(r.Rows))
+	}
+	// The player loses half the buy-in and keeps the premium of 10.
+	row := r.Rows[0]
+	if row.BackerPaid != 60 || row.PlayerShare != -40 || row.Makeup != 60 {
+		t.Errorf("backer paid %.2f, player share %.2f, makeup %.2f, want 60, -40, 60", row.BackerPaid, row.PlayerShare, row.Makeup)
+	}
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	// Statement is the yearly profit statement. Native amounts are in the
+	// currency of the tournament, the rest in the reporting currency.
+	Statement struct {
+		Year     int              `json:"year"`
+		Currency poker.Currency   `json:"currency"`
+		Lines    []StatementLine  `json:"lines"`
+		Totals   []StatementTotal `json:"totals"`
+		Wins     float64          `json:"wins"`
+		Losses   float64          `json:"losses"`
+		Net      float64          `json:"net"`
+	}
+
+	StatementLine struct {
+		Date        time.Time      `json:"date"`
+		ID          string         `json:"id"`
+		Name        string         `json:"name"`
+		Room        string         `json:"room"`
+		Currency    poker.Currency `json:"currency"`
+		NativeCost  float64        `json:"native_cost"`
+		NativePrize float64        `json:"native_prize"`
+		NativeNet   float64        `json:"native_net"`
+		Rate        float64        `json:"rate"`
+		Net         float64        `json:"net"`
+	}
+
+	// StatementTotal sums the lines of one room and currency.
+	StatementTotal struct {
+		Room         string         `json:"room"`
+		Currency     poker.Currency `json:"currency"`
+		Tournaments  int            `json:"tournaments"`
+		NativeWins   float64        `json:"native_wins"`
+		NativeLosses float64        `json:"native_losses"`
+		NativeNet    float64        `json:"native_net"`
+		Net          float64        `json:"net"`
+	}
+)
+
+// NewStatement builds the statement for the tournaments started in year,
+// converting every result at the rate of its day. The cost of a tournament
+// is that of all its entries.
+func NewStatement(ts []poker.Tournament, year int, currency poker.Currency, rates poker.Rates) Statement {
+	s := Statement{Year: year, Currency: currency, Lines: make([]StatementLine, 0)}
+	totals := make(map[[2]string]*StatementTotal)
+	for _, t := range sortedByStart(ts) {
+		if t.Started.Year() != year {
+			continue
+		}
+		native := t.Currency
+		if native == "" {
+			native = poker.USD
+		}
+		day := t.Started.Truncate(24 * time.Hour)
+		line := StatementLine{
+			Date:        t.Started,
+			ID:          t.ID,
+			Name:        t.Name,
+			Room:        t.Room,
+			Currency:    native,
+			NativeCost:  float64(native.FromDollar(t.TotalCost())),
+			NativePrize: float64(native.FromDollar(t.MyPrize)),
+			Rate:        rates.Convert(1, native, currency, day),
+		}
+		line.NativeNet = line.NativePrize - line.NativeCost
+		line.Net = line.NativeNet * line.Rate
+		s.Lines = append(s.Lines, line)
+
+		key := [2]string{t.Room, string(native)}
+		total, ok := totals[key]
+		if !ok {
+			total = &StatementTotal{Room: t.Room, Currency: native}
+			totals[key] = total
+		}
+		total.Tournaments++
+		total.NativeNet += line.NativeNet
+		total.Net += line.Net
+		if line.NativeNet >= 0 {
+			total.NativeWins += line.NativeNet
+			s.Wins += line.Net
+		} else {
+			total.NativeLosses += line.NativeNet
+			s.Losses += line.Net
+		}
+	}
+	s.Net = s.Wins + s.Losses
+	s.Totals = make([]StatementTotal, 0, len(totals))
+	for _, total := range totals {
+		s.Totals = append(s.Totals, *total)
+	}
+	sort.Slice(s.Totals, func(i, j int) bool {
+		if s.Totals[i].Room == s.Totals[j].Room {
+			return s.Totals[i].Currency < s.Totals[j].Currency
+		}
+		return s.Totals[i].Room < s.Totals[j].Room
+	})
+	return s
+}
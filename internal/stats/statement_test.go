@@ -0,0 +1,38 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+func TestStatementCountsReentries(t *testing.T) {
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		t    poker.Tournament
+		cost float64
+		net  float64
+	}{
+		{"single entry", poker.Tournament{ID: "1", BI: 10, BIRake: 1, MyPrize: 25, Started: day}, 10, 15},
+		{"re-entered", poker.Tournament{ID: "2", BI: 10, BIRake: 1, Reentries: 2, MyPrize: 20, Started: day}, 30, -10},
+		{"ticket", poker.Tournament{ID: "3", BI: 10, BIRake: 1, Reentries: 1, Free: true, Started: day}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStatement([]poker.Tournament{tt.t}, 2025, poker.USD, poker.Rates{})
+			if len(s.Lines) != 1 {
+				t.Fatalf("%d lines, want 1", len(s.Lines))
+			}
+			line := s.Lines[0]
+			if line.NativeCost != tt.cost || line.Net != tt.net {
+				t.Errorf("cost %.2f, net %.2f, want %.2f, %.2f", line.NativeCost, line.Net, tt.cost, tt.net)
+			}
+			// The rake report charges the same entries.
+			if rake := Rake([]poker.Tournament{tt.t}); rake.Total.Cost != tt.cost {
+				t.Errorf("rake report cost %.2f, want %.2f", rake.Total.Cost, tt.cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	VarianceOptions struct {
+		// Precision is the wanted half-width of the 95% ROI interval, in ROI points.
+		Precision float64
+		// Bootstrap is the number of resamples, 0 skips the bootstrap, at
+		// most MaxBootstrap.
+		Bootstrap int
+		Seed      int64
+	}
+
+	VarianceReport struct {
+		Tournaments int     `json:"tournaments"`
+		AvgCost     float64 `json:"avg_cost"`
+		MeanProfit  float64 `json:"mean_profit"`
+		// StdDev is the standard deviation of the profit of a single tournament.
+		StdDev   float64 `json:"std_dev"`
+		StdDevBI float64 `json:"std_dev_bi"`
+		ROI      float64 `json:"roi"`
+		ROIErr   float64 `json:"roi_std_err"`
+		ROILow   float64 `json:"roi_low"`
+		ROIHigh  float64 `json:"roi_high"`
+
+		Precision         float64    `json:"precision"`
+		TournamentsNeeded int        `json:"tournaments_needed"`
+		Bootstrap         *Bootstrap `json:"bootstrap,omitempty"`
+	}
+
+	// Bootstrap is the distribution of ROI over resampled tournament histories.
+	Bootstrap struct {
+		Samples   int     `json:"samples"`
+		Seed      int64   `json:"seed"`
+		Mean      float64 `json:"mean"`
+		Low       float64 `json:"low"`
+		High      float64 `json:"high"`
+		Histogram []Bin   `json:"histogram"`
+	}
+
+	// roiAcc keeps running sums for the ROI and the variance of profit (Welford).
+	roiAcc struct {
+		n     int
+		cost  float64
+		prize float64
+		mean  float64
+		m2    float64
+	}
+)
+
+const (
+	z95 = 1.96
+
+	DefaultPrecision = 10.0
+
+	// minROIBuyIn excludes micro buy-ins which distort the ROI.
+	minROIBuyIn = 0.2
+
+	bootstrapBins = 20
+	// MaxBootstrap bounds the resamples, each one draws the whole history.
+	MaxBootstrap = 10_000
+)
+
+func countsForROI(t poker.Tournament) bool {
+	return t.BI >= minROIBuyIn
+}
+
+func (a *roiAcc) add(t poker.Tournament) {
+	a.n++
+	a.cost += float64(t.Cost())
+	a.prize += float64(t.MyPrize)
+	profit := float64(t.Profit())
+	delta := profit - a.mean
+	a.mean += delta / float64(a.n)
+	a.m2 += delta * (profit - a.mean)
+}
+
+func (a *roiAcc) roi() float64 {
+	if a.cost == 0 {
+		return 0
+	}
+	return 100 * (a.prize - a.cost) / a.cost
+}
+
+func (a *roiAcc) stdDev() float64 {
+	if a.n < 2 {
+		return 0
+	}
+	return math.Sqrt(a.m2 / float64(a.n-1))
+}
+
+func (a *roiAcc) avgCost() float64 {
+	if a.n == 0 {
+		return 0
+	}
+	return a.cost / float64(a.n)
+}
+
+// roiErr is the standard error of the ROI in ROI points.
+func (a *roiAcc) roiErr() float64 {
+	avgCost := a.avgCost()
+	if a.n == 0 || avgCost == 0 {
+		return 0
+	}
+	return 100 * a.stdDev() / math.Sqrt(float64(a.n)) / avgCost
+}
+
+func (a *roiAcc) confidence() (float64, float64) {
+	roi, e := a.roi(), a.roiErr()
+	return roi - z95*e, roi + z95*e
+}
+
+// tournamentsNeeded is the sample size at which the 95% interval shrinks to ±precision ROI points.
+func (a *roiAcc) tournamentsNeeded(precision float64) int {
+	avgCost := a.avgCost()
+	if precision <= 0 || avgCost == 0 {
+		return 0
+	}
+	n := z95 * 100 * a.stdDev() / (avgCost * precision)
+	return int(math.Ceil(n * n))
+}
+
+// Variance estimates how much of the ROI is explained by luck.
+func Variance(ctx context.Context, ts []poker.Tournament, o VarianceOptions) (VarianceReport, error) {
+	if o.Bootstrap < 0 || o.Bootstrap > MaxBootstrap {
+		return VarianceReport{}, fmt.Errorf("bootstrap must be between 0 and %d", MaxBootstrap)
+	}
+	if o.Precision <= 0 {
+		o.Precision = DefaultPrecision
+	}
+	var (
+		acc     roiAcc
+		sampled []poker.Tournament
+	)
+	for _, t := range ts {
+		if !countsForROI(t) {
+			continue
+		}
+		acc.add(t)
+		sampled = append(sampled, t)
+	}
+	r := VarianceReport{
+		Tournaments:       acc.n,
+		AvgCost:           acc.avgCost(),
+		MeanProfit:        acc.mean,
+		StdDev:            acc.stdDev(),
+		ROI:               acc.roi(),
+		ROIErr:            acc.roiErr(),
+		Precision:         o.Precision,
+		TournamentsNeeded: acc.tournamentsNeeded(o.Precision),
+	}
+	if r.AvgCost > 0 {
+		r.StdDevBI = r.StdDev / r.AvgCost
+	}
+	r.ROILow, r.ROIHigh = acc.confidence()
+	if o.Bootstrap > 0 && len(sampled) > 0 {
+		b, err := bootstrap(ctx, sampled, o.Bootstrap, o.Seed)
+		if err != nil {
+			return VarianceReport{}, err
+		}
+		r.Bootstrap = b
+	}
+	return r, nil
+}
+
+func bootstrap(ctx context.Context, ts []poker.Tournament, samples int, seed int64) (*Bootstrap, error) {
+	rnd := rand.New(rand.NewSource(seed))
+	rois := make([]float64, 0, samples)
+	var sum float64
+	for i := 0; i < samples; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var cost, prize float64
+		for range ts {
+			t := ts[rnd.Intn(len(ts))]
+			cost += float64(t.Cost())
+			prize += float64(t.MyPrize)
+		}
+		if cost == 0 {
+			continue
+		}
+		roi := 100 * (prize - cost) / cost
+		rois = append(rois, roi)
+		sum += roi
+	}
+	b := &Bootstrap{Samples: len(rois), Seed: seed}
+	if len(rois) == 0 {
+		return b, nil
+	}
+	sort.Float64s(rois)
+	b.Mean = sum / float64(len(rois))
+	b.Low = quantile(rois, 0.025)
+	b.High = quantile(rois, 0.975)
+	b.Histogram = histogram(rois, bootstrapBins)
+	return b, nil
+}
+
+// quantile expects sorted values.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
+
+func formatBin(from, to float64) string {
+	return fmt.Sprintf("%.1f..%.1f", from, to)
+}
+
+// histogram expects sorted values.
+func histogram(sorted []float64, bins int) []Bin {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if hi == lo {
+		return []Bin{{Label: formatBin(lo, hi), From: lo, To: hi, Count: len(sorted)}}
+	}
+	width := (hi - lo) / float64(bins)
+	res := make([]Bin, bins)
+	for i := range res {
+		res[i].From = lo + float64(i)*width
+		res[i].To = lo + float64(i+1)*width
+		res[i].Label = formatBin(res[i].From, res[i].To)
+	}
+	for _, v := range sorted {
+		i := min(int((v-lo)/width), bins-1)
+		res[i].Count++
+	}
+	return res
+}
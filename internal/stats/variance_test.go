@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+// swings loses and doubles a buy-in of 10 in turn, with one micro buy-in
+// left out of the ROI.
+var swings = []poker.Tournament{{BI: 10}, {BI: 10, MyPrize: 20}, {BI: 10}, {BI: 10, MyPrize: 20}, {BI: 0.1, MyPrize: 50}}
+
+func TestVariance(t *testing.T) {
+	r, err := Variance(context.Background(), swings, VarianceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tournaments != 4 || r.ROI != 0 || r.MeanProfit != 0 || r.Precision != DefaultPrecision {
+		t.Errorf("%+v", r)
+	}
+	if want := math.Sqrt(400.0 / 3); math.Abs(r.StdDev-want) > 1e-9 || math.Abs(r.StdDevBI-want/10) > 1e-9 {
+		t.Errorf("std dev %.4f (%.4f buy-ins), want %.4f", r.StdDev, r.StdDevBI, want)
+	}
+	if r.ROILow != -r.ROIHigh || r.ROIHigh <= 0 {
+		t.Errorf("interval %.2f..%.2f", r.ROILow, r.ROIHigh)
+	}
+	// 1.96 * 100 * 11.547 / (10 * 10) squared.
+	if r.TournamentsNeeded != 513 {
+		t.Errorf("tournaments needed %d, want 513", r.TournamentsNeeded)
+	}
+}
+
+func TestVarianceBootstrap(t *testing.T) {
+	tests := []struct {
+		name      string
+		ts        []poker.Tournament
+		samples   int
+		err       bool
+		low, high float64
+	}{
+		{"none", swings, 0, false, 0, 0},
+		{"negative", swings, -1, true, 0, 0},
+		{"too many", swings, MaxBootstrap + 1, true, 0, 0},
+		{"steady", []poker.Tournament{{BI: 10, MyPrize: 15}, {BI: 10, MyPrize: 15}}, 100, false, 50, 50},
+		{"swings", swings, 1000, false, -100, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := VarianceOptions{Bootstrap: tt.samples, Seed: 7}
+			r, err := Variance(context.Background(), tt.ts, o)
+			if (err != nil) != tt.err {
+				t.Fatalf("error %v", err)
+			}
+			if err != nil || tt.samples == 0 {
+				if r.Bootstrap != nil {
+					t.Errorf("bootstrap %+v, want none", r.Bootstrap)
+				}
+				return
+			}
+			b := r.Bootstrap
+			if b.Samples != tt.samples || b.Low < tt.low || b.High > tt.high || b.Low > b.High {
+				t.Errorf("%d samples in %.2f..%.2f, want %d within %.2f..%.2f", b.Samples, b.Low, b.High, tt.samples, tt.low, tt.high)
+			}
+			// The same seed draws the same histories.
+			again, _ := Variance(context.Background(), tt.ts, o)
+			if !reflect.DeepEqual(b, again.Bootstrap) {
+				t.Errorf("seed %d drew %+v then %+v", o.Seed, b, again.Bootstrap)
+			}
+		})
+	}
+}
+
+func TestVarianceCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Variance(ctx, swings, VarianceOptions{Bootstrap: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("error %v, want %v", err, context.Canceled)
+	}
+}
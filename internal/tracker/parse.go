@@ -0,0 +1,335 @@
+package tracker
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VOVAN1993/poker_hand/internal/poker"
+)
+
+type (
+	RowError struct {
+		// Line in the file, the header is line 1.
+		Line  int    `json:"line"`
+		Error string `json:"error"`
+	}
+
+	// Result is what was read from a file, before looking at the database.
+	Result struct {
+		Rows        int
+		Tournaments []poker.Tournament
+		// Repeated are the IDs met more than once, only the first row is kept.
+		Repeated []string
+		Errors   []RowError
+	}
+
+	// Report describes an import, the tournaments are the new ones.
+	Report struct {
+		DryRun      bool               `json:"dry_run"`
+		Rows        int                `json:"rows"`
+		New         int                `json:"new"`
+		Existing    []string           `json:"existing"`
+		Repeated    []string           `json:"repeated"`
+		Errors      []RowError         `json:"errors"`
+		Tournaments []poker.Tournament `json:"tournaments"`
+	}
+)
+
+var numberRegexp = regexp.MustCompile(`-?\d+`)
+
+// Parse reads a CSV export with a header line. Rows that cannot be read are
+// reported and skipped, only a broken header fails the whole file.
+func Parse(r io.Reader, p Profile) (Result, error) {
+	if err := p.Validate(); err != nil {
+		return Result{}, err
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if p.Comma != "" {
+		reader.Comma = []rune(p.Comma)[0]
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return Result{}, fmt.Errorf("reading header: %w", err)
+	}
+	index, err := columnIndex(header, p)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Tournaments: make([]poker.Tournament, 0), Repeated: make([]string, 0), Errors: make([]RowError, 0)}
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return Result{}, err
+			}
+			res.Rows++
+			res.Errors = append(res.Errors, RowError{Line: parseErr.Line, Error: parseErr.Err.Error()})
+			continue
+		}
+		if emptyRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		res.Rows++
+		t, err := row{record: record, index: index}.tournament(p)
+		if err != nil {
+			res.Errors = append(res.Errors, RowError{Line: line, Error: err.Error()})
+			continue
+		}
+		if seen[t.ID] {
+			res.Repeated = append(res.Repeated, t.ID)
+			continue
+		}
+		seen[t.ID] = true
+		res.Tournaments = append(res.Tournaments, t)
+	}
+	return res, nil
+}
+
+// columnIndex finds the column of every mapped field present in the header.
+func columnIndex(header []string, p Profile) (map[Field]int, error) {
+	byHeader := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := byHeader[h]; !ok {
+			byHeader[h] = i
+		}
+	}
+	index := make(map[Field]int)
+	for f, headers := range p.Columns {
+		for _, h := range headers {
+			if i, ok := byHeader[strings.ToLower(strings.TrimSpace(h))]; ok {
+				index[f] = i
+				break
+			}
+		}
+	}
+	for _, f := range requiredFields {
+		if _, ok := index[f]; !ok {
+			return nil, fmt.Errorf("no column for %q, expected one of %q", f, p.Columns[f])
+		}
+	}
+	return index, nil
+}
+
+func emptyRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+type row struct {
+	record []string
+	index  map[Field]int
+}
+
+func (r row) get(f Field) string {
+	i, ok := r.index[f]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r row) tournament(p Profile) (poker.Tournament, error) {
+	t := poker.Tournament{
+		ID:   r.get(FieldID),
+		Name: r.get(FieldName),
+		Room: r.get(FieldRoom),
+	}
+	if t.ID == "" {
+		return t, errors.New("empty tournament id")
+	}
+	if t.Room == "" {
+		t.Room = p.Room
+	}
+
+	var err error
+	if t.Started, err = r.time(FieldStarted, p); err != nil {
+		return t, err
+	}
+	if t.Started.IsZero() {
+		return t, errors.New("empty start time")
+	}
+	finished, err := r.time(FieldFinished, p)
+	if err != nil {
+		return t, err
+	}
+	t.SetFinished(finished)
+
+	// The row currency comes from its own column or the buy-in symbol.
+	t.Currency, err = parseCurrency(r.get(FieldCurrency))
+	if err != nil {
+		return t, err
+	}
+	if t.Currency == "" {
+		t.Currency = symbolCurrency(r.get(FieldBuyIn))
+	}
+
+	bi, err := r.buyIn(p)
+	if err != nil {
+		return t, err
+	}
+	t.BI, t.BIRake, t.BIBounty = bi.Total(), bi.Rake, bi.Bounty
+
+	if t.TotalPrizePool, err = r.money(FieldPrizePool); err != nil {
+		return t, err
+	}
+	if t.MyPrize, err = r.money(FieldPrize); err != nil {
+		return t, err
+	}
+	if t.Players, err = r.int(FieldPlayers); err != nil {
+		return t, err
+	}
+	if t.MyPlace, err = r.int(FieldPlace); err != nil {
+		return t, err
+	}
+	if t.Reentries, err = r.int(FieldReentries); err != nil {
+		return t, err
+	}
+
+	for _, v := range []*float32{&t.BI, &t.BIRake, &t.BIBounty, &t.TotalPrizePool, &t.MyPrize} {
+		*v = t.Currency.ToDollar(*v)
+	}
+	t.Type = tournamentType(r.get(FieldType), t.Name)
+	return t, nil
+}
+
+// buyIn reads either a "prize+rake+bounty" buy-in or a single amount
+// completed by the rake and bounty columns.
+func (r row) buyIn(p Profile) (poker.BuyIn, error) {
+	value := r.get(FieldBuyIn)
+	if strings.Contains(value, "+") {
+		var parts []float32
+		for _, part := range strings.Split(value, "+") {
+			amount, err := parseMoney(part)
+			if err != nil {
+				return poker.BuyIn{}, fmt.Errorf("%s: %w", FieldBuyIn, err)
+			}
+			parts = append(parts, amount)
+		}
+		return poker.NewBuyIn(parts), nil
+	}
+	total, err := r.money(FieldBuyIn)
+	if err != nil {
+		return poker.BuyIn{}, err
+	}
+	rake, err := r.money(FieldRake)
+	if err != nil {
+		return poker.BuyIn{}, err
+	}
+	bounty, err := r.money(FieldBounty)
+	if err != nil {
+		return poker.BuyIn{}, err
+	}
+	if p.BuyInExcludesRake {
+		total += rake
+	}
+	if total < rake+bounty {
+		return poker.BuyIn{}, fmt.Errorf("buy-in %v is less than its rake and bounty", total)
+	}
+	return poker.BuyIn{Prize: total - rake - bounty, Rake: rake, Bounty: bounty}, nil
+}
+
+func (r row) money(f Field) (float32, error) {
+	v, err := parseMoney(r.get(f))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", f, err)
+	}
+	return v, nil
+}
+
+// int reads whole numbers, ignoring suffixes like in "3rd".
+func (r row) int(f Field) (int, error) {
+	value := r.get(f)
+	if value == "" || value == "-" {
+		return 0, nil
+	}
+	match := numberRegexp.FindString(strings.ReplaceAll(value, ",", ""))
+	if match == "" {
+		return 0, fmt.Errorf("%s: invalid number %q", f, value)
+	}
+	return strconv.Atoi(match)
+}
+
+func (r row) time(f Field, p Profile) (time.Time, error) {
+	value := r.get(f)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range p.layouts() {
+		if t, err := time.Parse(layout, value); err == nil {
+			// Times are kept as wall clock times like in the summaries.
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s: unknown time format %q", f, value)
+}
+
+// parseMoney reads amounts like "$1,234.50" or "1234.5 €", empty is zero.
+func parseMoney(s string) (float32, error) {
+	value := strings.TrimSpace(s)
+	for _, symbol := range []string{"$", "€", "¥", "USD", "EUR", "CNY", ",", " "} {
+		value = strings.ReplaceAll(value, symbol, "")
+	}
+	if value == "" || value == "-" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return float32(v), nil
+}
+
+func parseCurrency(s string) (poker.Currency, error) {
+	switch s = strings.ToUpper(strings.TrimSpace(s)); s {
+	case "":
+		return "", nil
+	case "$":
+		return poker.USD, nil
+	case "€":
+		return poker.EUR, nil
+	case "¥":
+		return poker.CNY, nil
+	}
+	return poker.ParseCurrency(s)
+}
+
+func symbolCurrency(amount string) poker.Currency {
+	switch {
+	case strings.Contains(amount, "€"):
+		return poker.EUR
+	case strings.Contains(amount, "¥"):
+		return poker.CNY
+	}
+	return poker.USD
+}
+
+// tournamentType takes a known type from the type column, otherwise it is guessed from the name.
+func tournamentType(value, name string) poker.TournamentType {
+	for _, t := range poker.TournamentTypes {
+		if strings.EqualFold(value, string(t)) {
+			return t
+		}
+	}
+	t, _ := poker.TypeByName(name)
+	return t
+}
@@ -0,0 +1,185 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Field is a tournament field a CSV column can be mapped to.
+	Field string
+
+	// Profile maps the columns of a tracker export to tournament fields.
+	Profile struct {
+		Name string `json:"name"`
+		// Columns lists the accepted headers of every field, compared case insensitively.
+		Columns map[Field][]string `json:"columns"`
+		// TimeLayouts are tried in order for started and finished, after RFC 3339.
+		TimeLayouts []string `json:"time_layouts,omitempty"`
+		// Comma separates the columns, ',' when empty.
+		Comma string `json:"comma,omitempty"`
+		// BuyInExcludesRake is set when the buy-in column is the prize pool part only.
+		BuyInExcludesRake bool `json:"buy_in_excludes_rake,omitempty"`
+		// Room is used for rows without a room column.
+		Room string `json:"room,omitempty"`
+	}
+)
+
+const (
+	FieldID        Field = "id"
+	FieldName      Field = "name"
+	FieldType      Field = "type"
+	FieldRoom      Field = "room"
+	FieldCurrency  Field = "currency"
+	FieldStarted   Field = "started"
+	FieldFinished  Field = "finished"
+	FieldBuyIn     Field = "bi"
+	FieldRake      Field = "bi_rake"
+	FieldBounty    Field = "bi_bounty"
+	FieldPlayers   Field = "players"
+	FieldPrizePool Field = "total_prize_pool"
+	FieldPlace     Field = "my_place"
+	FieldPrize     Field = "my_prize"
+	FieldReentries Field = "reentries"
+)
+
+var Fields = []Field{
+	FieldID, FieldName, FieldType, FieldRoom, FieldCurrency, FieldStarted, FieldFinished,
+	FieldBuyIn, FieldRake, FieldBounty, FieldPlayers, FieldPrizePool, FieldPlace, FieldPrize, FieldReentries,
+}
+
+// requiredFields must be mapped by every profile.
+var requiredFields = []Field{FieldID, FieldStarted, FieldBuyIn, FieldPrize}
+
+// Profiles are the built-in mappings. "generic" reads the tournaments export of this tool.
+var Profiles = map[string]Profile{
+	"generic": {
+		Name: "generic",
+		Columns: map[Field][]string{
+			FieldID: {"id"}, FieldName: {"name"}, FieldType: {"type"}, FieldRoom: {"room"},
+			FieldCurrency: {"currency"}, FieldStarted: {"started"}, FieldFinished: {"finished"},
+			FieldBuyIn: {"bi"}, FieldRake: {"bi_rake"}, FieldBounty: {"bi_bounty"},
+			FieldPlayers: {"players"}, FieldPrizePool: {"total_prize_pool"}, FieldPlace: {"my_place"},
+			FieldPrize: {"my_prize"}, FieldReentries: {"reentries"},
+		},
+	},
+	"holdem-manager": {
+		Name: "holdem-manager",
+		Columns: map[Field][]string{
+			FieldID: {"Tournament ID", "Tournament #"}, FieldName: {"Description", "Tournament Name"},
+			FieldRoom: {"Site"}, FieldCurrency: {"Currency"}, FieldStarted: {"Start Date", "Date"},
+			FieldFinished: {"End Date"}, FieldBuyIn: {"Buy-In", "Buyin"}, FieldRake: {"Rake"},
+			FieldBounty: {"Bounty"}, FieldPlayers: {"Players", "Entrants"}, FieldPrizePool: {"Prize Pool"},
+			FieldPlace: {"Finish Position", "Position"}, FieldPrize: {"Winnings", "Won"},
+			FieldReentries: {"Rebuys", "Re-Entries"},
+		},
+		TimeLayouts:       []string{"1/2/2006 3:04:05 PM", "1/2/2006 15:04", "2006-01-02 15:04:05"},
+		BuyInExcludesRake: true,
+	},
+	"pokertracker": {
+		Name: "pokertracker",
+		Columns: map[Field][]string{
+			FieldID: {"Tourney #", "Tournament"}, FieldName: {"Name", "Description"}, FieldRoom: {"Site"},
+			FieldCurrency: {"Currency"}, FieldStarted: {"Start Time", "Date"}, FieldFinished: {"End Time"},
+			FieldBuyIn: {"Buy-In"}, FieldRake: {"Fee"}, FieldBounty: {"Bounty"},
+			FieldPlayers: {"Entrants", "Players"}, FieldPrizePool: {"Prize Pool"}, FieldPlace: {"Position", "Finish"},
+			FieldPrize: {"Won", "Winnings"}, FieldReentries: {"Re-Entries", "Rebuys"},
+		},
+		TimeLayouts:       []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "01/02/2006 15:04"},
+		BuyInExcludesRake: true,
+	},
+	"sharkscope": {
+		Name: "sharkscope",
+		Columns: map[Field][]string{
+			FieldID: {"Game ID"}, FieldName: {"Name"}, FieldRoom: {"Network"}, FieldCurrency: {"Currency"},
+			FieldStarted: {"Date", "Start Date"}, FieldFinished: {"End Date"}, FieldBuyIn: {"Stake"},
+			FieldRake: {"Rake"}, FieldPlayers: {"Entrants"}, FieldPrizePool: {"Prize Pool"},
+			FieldPlace: {"Position"}, FieldPrize: {"Prize"}, FieldReentries: {"Re-entries"},
+		},
+		TimeLayouts:       []string{"2006-01-02 15:04", "2006-01-02 15:04:05"},
+		BuyInExcludesRake: true,
+	},
+}
+
+// ProfileNames lists the built-in profiles in alphabetical order.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetProfile returns a copy of a built-in profile that is safe to modify.
+func GetProfile(name string) (Profile, error) {
+	if name == "" {
+		name = "generic"
+	}
+	p, ok := Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q, known: %s", name, strings.Join(ProfileNames(), ", "))
+	}
+	columns := make(map[Field][]string, len(p.Columns))
+	for f, headers := range p.Columns {
+		columns[f] = append([]string(nil), headers...)
+	}
+	p.Columns = columns
+	p.TimeLayouts = append([]string(nil), p.TimeLayouts...)
+	return p, nil
+}
+
+// Map points a field to a header, replacing the headers of the profile.
+// It accepts "field=Header".
+func (p *Profile) Map(mapping string) error {
+	field, header, ok := strings.Cut(mapping, "=")
+	if !ok || strings.TrimSpace(header) == "" {
+		return fmt.Errorf("invalid mapping %q, expected field=Header", mapping)
+	}
+	f := Field(strings.TrimSpace(field))
+	if !knownField(f) {
+		return fmt.Errorf("unknown field %q", field)
+	}
+	if p.Columns == nil {
+		p.Columns = make(map[Field][]string)
+	}
+	p.Columns[f] = []string{strings.TrimSpace(header)}
+	return nil
+}
+
+func (p Profile) Validate() error {
+	for f := range p.Columns {
+		if !knownField(f) {
+			return fmt.Errorf("unknown field %q", f)
+		}
+	}
+	for _, f := range requiredFields {
+		if len(p.Columns[f]) == 0 {
+			return fmt.Errorf("field %q is not mapped", f)
+		}
+	}
+	if len([]rune(p.Comma)) > 1 {
+		return fmt.Errorf("invalid separator %q", p.Comma)
+	}
+	for _, layout := range p.TimeLayouts {
+		if layout == "" {
+			return fmt.Errorf("empty time layout")
+		}
+	}
+	return nil
+}
+
+func knownField(f Field) bool {
+	for _, known := range Fields {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Profile) layouts() []string {
+	return append([]string{time.RFC3339, "2006-01-02 15:04:05", "2006/01/02 15:04:05"}, p.TimeLayouts...)
+}